@@ -18,6 +18,7 @@ type Collection struct {
 	Metadata map[string]interface{}
 
 	cfg   *config.Config
+	files []*pages.StaticFile
 	pages []pages.Page
 	site  Site
 }
@@ -61,11 +62,17 @@ func (c *Collection) IsPostsCollection() bool { return c.Name == postsName }
 // Output returns a bool indicating whether files in this collection should be written.
 func (c *Collection) Output() bool { return templates.VariableMap(c.Metadata).Bool("output", false) }
 
-// Pages is a slice of the collection's pages. Pages in the Post collection are ordered by date.
+// Pages is a slice of the collection's pages. Pages in the Post collection are ordered by date,
+// unless the collection metadata specifies sort_by or order.
 func (c *Collection) Pages() []pages.Page {
 	return c.pages
 }
 
+// Files is a slice of the collection's static files.
+func (c *Collection) Files() []*pages.StaticFile {
+	return c.files
+}
+
 // Render renders the collection's pages.
 func (c *Collection) Render() error {
 	for _, p := range c.Pages() {
@@ -85,7 +92,7 @@ func (c *Collection) ToLiquid() interface{} {
 		map[string]interface{}{
 			"label":              c.Name,
 			"docs":               c.pages,
-			"files":              []string{},
+			"files":              c.files,
 			"relative_directory": strings.TrimSuffix(c.PathPrefix(), "/"),
 			"directory":          c.AbsDir(),
 		}))
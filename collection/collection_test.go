@@ -61,3 +61,43 @@ func Test_ReadPages(t *testing.T) {
 	require.Equal(t, pages[0], pages[1].FrontMatter()["previous"])
 	require.Equal(t, nil, pages[1].FrontMatter()["next"])
 }
+
+func Test_ReadPages_sort(t *testing.T) {
+	site := siteFake{config.FromString("source: testdata")}
+	relpaths := func(c *Collection) (out []string) {
+		for _, p := range c.Pages() {
+			out = append(out, c.relativePath(p))
+		}
+		return
+	}
+
+	c := New(site, "docs", map[string]interface{}{})
+	require.NoError(t, c.ReadPages())
+	require.Equal(t, []string{"a.md", "b.md", "c.md"}, relpaths(c))
+
+	c = New(site, "docs", map[string]interface{}{"sort_by": "weight"})
+	require.NoError(t, c.ReadPages())
+	require.Equal(t, []string{"b.md", "a.md", "c.md"}, relpaths(c))
+
+	c = New(site, "docs", map[string]interface{}{"order": []interface{}{"c.md", "a.md"}})
+	require.NoError(t, c.ReadPages())
+	require.Equal(t, []string{"c.md", "a.md", "b.md"}, relpaths(c))
+
+	pages := c.Pages()
+	require.Equal(t, nil, pages[0].FrontMatter()["previous"])
+	require.Equal(t, pages[1], pages[0].FrontMatter()["next"])
+	require.Equal(t, pages[1], pages[2].FrontMatter()["previous"])
+	require.Equal(t, nil, pages[2].FrontMatter()["next"])
+}
+
+func TestCollection_Files(t *testing.T) {
+	site := siteFake{config.FromString("source: testdata")}
+	c := New(site, "docs", map[string]interface{}{})
+	require.NoError(t, c.ReadPages())
+	require.Len(t, c.Files(), 1)
+	require.Equal(t, "/docs/images/logo.gif", c.Files()[0].URL())
+
+	c = New(site, "docs", map[string]interface{}{"permalink": "/:collection/:name/"})
+	require.NoError(t, c.ReadPages())
+	require.Equal(t, "/docs/logo.gif", c.Files()[0].URL())
+}
@@ -3,8 +3,9 @@ package collection
 import (
 	"os"
 	"path/filepath"
-	"sort"
+	"strings"
 
+	"github.com/osteele/gojekyll/config"
 	"github.com/osteele/gojekyll/frontmatter"
 	"github.com/osteele/gojekyll/pages"
 	"github.com/osteele/gojekyll/utils"
@@ -12,18 +13,20 @@ import (
 
 // ReadPages scans the file system for collection pages, and adds them to c.Pages.
 func (c *Collection) ReadPages() error {
-	if c.IsPostsCollection() && c.cfg.Drafts {
-		if err := c.scanDirectory(draftsPath); err != nil {
+	dirs := []string{c.PathPrefix()}
+	if c.IsPostsCollection() {
+		var err error
+		if dirs, err = c.PostDirectories(); err != nil {
 			return err
 		}
 	}
-	if err := c.scanDirectory(c.PathPrefix()); err != nil {
-		return err
-	}
-	if c.IsPostsCollection() {
-		sort.Sort(pagesByDate{c.pages})
-		addPrevNext(c.pages)
+	for _, dir := range dirs {
+		if err := c.scanDirectory(dir); err != nil {
+			return err
+		}
 	}
+	c.sortPages()
+	addPrevNext(c.pages)
 	return nil
 }
 
@@ -43,9 +46,42 @@ func addPrevNext(ps []pages.Page) {
 	}
 }
 
+// PostDirectories returns the site-relative paths of the _posts directories,
+// and of the _drafts directories if drafts are enabled. These can be at any
+// depth; the directories above them are the posts' categories.
+func (c *Collection) PostDirectories() ([]string, error) {
+	var (
+		sitePath = c.cfg.Source
+		dirs     []string
+	)
+	err := filepath.Walk(sitePath, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		siteRel := utils.MustRel(sitePath, filename)
+		name := info.Name()
+		switch {
+		case !info.IsDir() || siteRel == ".":
+			return nil
+		case name == "_posts" || (name == draftsPath && c.cfg.Drafts):
+			dirs = append(dirs, siteRel)
+			return filepath.SkipDir
+		case strings.HasPrefix(name, "_"), strings.HasPrefix(name, "."), c.site.Exclude(siteRel):
+			return filepath.SkipDir
+		default:
+			return nil
+		}
+	})
+	return dirs, err
+}
+
 // scanDirectory scans the file system for collection pages, and adds them to c.Pages.
 //
-// This function is distinct from ReadPages so that the posts collection can call it twice.
+// This function is distinct from ReadPages so that the posts collection can call it for each
+// _posts and _drafts directory.
 func (c *Collection) scanDirectory(dirname string) error {
 	sitePath := c.cfg.Source
 	dir := filepath.Join(sitePath, dirname)
@@ -56,11 +92,13 @@ func (c *Collection) scanDirectory(dirname string) error {
 			}
 			return err
 		}
-		siteRel := utils.MustRel(sitePath, filename)
+		// Exclude treats any underscore directory below the top level as
+		// excluded, so test the path relative to the directory's parent.
+		parentRel := utils.MustRel(filepath.Dir(dir), filename)
 		switch {
 		case info.IsDir():
 			return nil
-		case c.site.Exclude(siteRel):
+		case c.site.Exclude(parentRel):
 			return nil
 		default:
 			return c.readPost(filename, utils.MustRel(dir, filename))
@@ -70,6 +108,10 @@ func (c *Collection) scanDirectory(dirname string) error {
 
 func (c *Collection) readPost(path string, rel string) error {
 	siteRel := utils.MustRel(c.cfg.Source, path)
+	typename := c.Name
+	if c.IsPostsCollection() && isDraftPath(filepath.ToSlash(siteRel)) {
+		typename = config.DraftsScopeType
+	}
 	strategy := c.strategy()
 	switch {
 	case !strategy.isCollectible(rel):
@@ -80,17 +122,29 @@ func (c *Collection) readPost(path string, rel string) error {
 	fm := frontmatter.FrontMatter{
 		"collection": c.Name,
 		"permalink":  c.PermalinkPattern(),
-	}.Merged(c.cfg.GetFrontMatterDefaults(c.Name, siteRel))
+	}.Merged(c.cfg.GetFrontMatterDefaults(typename, siteRel))
 	strategy.parseFilename(rel, fm)
 	f, err := pages.NewFile(c.site, path, filepath.ToSlash(rel), fm)
 	switch {
 	case err != nil:
 		return err
-	case f.IsStatic():
+	case !f.Published() && !c.cfg.Unpublished:
 		return nil
-	case f.Published() || c.cfg.Unpublished:
-		p := f.(pages.Page) // f.Static() guarantees this
+	case f.IsStatic():
+		c.files = append(c.files, f.(*pages.StaticFile))
+	default:
+		p := f.(pages.Page) // !f.Static() guarantees this
 		c.pages = append(c.pages, p)
 	}
 	return nil
 }
+
+// isDraftPath returns true if a slash-separated site-relative path is inside
+// a _drafts directory.
+func isDraftPath(rel string) bool {
+	dirs, ok := pages.PostDirectoryCategories(rel)
+	if !ok {
+		return false
+	}
+	return strings.Split(rel, "/")[len(dirs)] == draftsPath
+}
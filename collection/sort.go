@@ -1,6 +1,11 @@
 package collection
 
 import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
 	"github.com/osteele/gojekyll/pages"
 )
 
@@ -22,3 +27,125 @@ func (p pagesByDate) Swap(i, j int) {
 	pages := p.pages
 	pages[i], pages[j] = pages[j], pages[i]
 }
+
+// sortPages orders the collection's pages. This implements the
+// collection sort_by and order metadata https://jekyllrb.com/docs/collections/#sort-by-front-matter-key.
+// Posts are otherwise ordered by date, and the pages of other collections
+// by relative path.
+func (c *Collection) sortPages() {
+	ps := c.pages
+	sort.SliceStable(ps, func(i, j int) bool {
+		return c.relativePath(ps[i]) < c.relativePath(ps[j])
+	})
+	if c.IsPostsCollection() {
+		sort.Stable(pagesByDate{ps})
+	}
+	switch order := c.orderMetadata(); {
+	case len(order) > 0:
+		c.sortPagesByOrder(order)
+	case c.sortByMetadata() != "":
+		sortPagesByKey(ps, c.sortByMetadata())
+	}
+}
+
+func (c *Collection) sortByMetadata() string {
+	s, _ := c.Metadata["sort_by"].(string)
+	return s
+}
+
+func (c *Collection) orderMetadata() (order []string) {
+	switch value := c.Metadata["order"].(type) {
+	case []string:
+		order = value
+	case []interface{}:
+		for _, item := range value {
+			order = append(order, fmt.Sprint(item))
+		}
+	}
+	return
+}
+
+// relativePath returns the page's path relative to the collection directory.
+func (c *Collection) relativePath(p pages.Page) string {
+	dir := filepath.Join(c.cfg.Source, c.PathPrefix())
+	if rel, err := filepath.Rel(dir, p.Source()); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return p.Source()
+}
+
+// sortPagesByOrder moves the pages named in order to the front, in that order.
+// The remaining pages keep their relative order.
+func (c *Collection) sortPagesByOrder(order []string) {
+	index := map[string]int{}
+	for i, name := range order {
+		index[name] = i
+	}
+	ps := c.pages
+	sort.SliceStable(ps, func(i, j int) bool {
+		a, aok := index[c.relativePath(ps[i])]
+		b, bok := index[c.relativePath(ps[j])]
+		switch {
+		case aok && bok:
+			return a < b
+		default:
+			return aok && !bok
+		}
+	})
+}
+
+// sortPagesByKey sorts by a front matter variable. Pages that don't define the
+// variable are placed last, and keep their relative order.
+func sortPagesByKey(ps []pages.Page, key string) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		a, aok := ps[i].FrontMatter()[key]
+		b, bok := ps[j].FrontMatter()[key]
+		switch {
+		case aok && bok:
+			return lessValue(a, b)
+		default:
+			return aok && !bok
+		}
+	})
+}
+
+// lessValue compares front matter values of the same type by value, and
+// other values by their string representation.
+func lessValue(a, b interface{}) bool {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok {
+			return a < b
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			return a < b
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return a < b
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Before(b)
+		}
+	}
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af < bf
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osteele/gojekyll/site"
+)
+
+var check = app.Command("check", "Check the site's internal links, anchors, and asset references")
+
+func checkCommand(site *site.Site) error {
+	logger.label("Checking:", "links in %d routes", len(site.Routes))
+	broken, err := site.CheckLinks()
+	if err != nil {
+		return err
+	}
+	for _, l := range broken {
+		fmt.Fprintln(os.Stderr, l)
+	}
+	if n := len(broken); n > 0 {
+		inflect := map[bool]string{true: "", false: "s"}[n == 1]
+		return fmt.Errorf("found %d broken reference%s", n, inflect)
+	}
+	logger.label("", "no broken references")
+	return nil
+}
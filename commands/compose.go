@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/frontmatter"
+	"github.com/osteele/gojekyll/site"
+	"github.com/osteele/gojekyll/utils"
+	"github.com/osteele/liquid/evaluator"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// These commands emulate jekyll-compose. The compose section of
+// _config.yml supplies additional front matter for new files:
+//
+//	compose:
+//	  default_front_matter:
+//	    posts:
+//	      description:
+//	      tags: []
+//	    drafts: ...
+//	    pages: ...
+var (
+	post      = app.Command("post", "Create a post")
+	postTitle = post.Arg("TITLE", "Post title").Required().Strings()
+
+	draft      = app.Command("draft", "Create a draft post")
+	draftTitle = draft.Arg("TITLE", "Draft title").Required().Strings()
+
+	page      = app.Command("page", "Create a page")
+	pageTitle = page.Arg("TITLE", "Page title").Required().Strings()
+
+	publish     = app.Command("publish", "Move a draft into the _posts directory")
+	publishPath = publish.Arg("PATH", "Draft file").Required().String()
+
+	unpublish     = app.Command("unpublish", "Move a post into the _drafts directory")
+	unpublishPath = unpublish.Arg("PATH", "Post file").Required().String()
+)
+
+// Command-line options for the compose commands
+var composeOptions struct {
+	date, ext, layout string
+	force             bool
+}
+
+// The format of the date front matter variable of new posts
+const composeDateFormat = "2006-01-02 15:04:05 -0700"
+
+func init() {
+	for _, cmd := range []*kingpin.CmdClause{post, draft, page} {
+		cmd.Flag("layout", "Layout; defaults to post, or to page for a page").Short('l').StringVar(&composeOptions.layout)
+		cmd.Flag("extension", "File extension").Short('x').Default("md").StringVar(&composeOptions.ext)
+	}
+	for _, cmd := range []*kingpin.CmdClause{post, draft, page, publish, unpublish} {
+		cmd.Flag("force", "Overwrite an existing file").Short('f').BoolVar(&composeOptions.force)
+	}
+	for _, cmd := range []*kingpin.CmdClause{post, publish} {
+		cmd.Flag("date", "Post date; defaults to now").StringVar(&composeOptions.date)
+	}
+}
+
+func composeCommand(cmd string) error {
+	site, err := site.FromDirectory(*source, options)
+	if err != nil {
+		return err
+	}
+	cfg := site.Config()
+	switch cmd {
+	case post.FullCommand():
+		return postCommand(cfg)
+	case draft.FullCommand():
+		return draftCommand(cfg)
+	case page.FullCommand():
+		return pageCommand(cfg)
+	case publish.FullCommand():
+		return publishCommand(cfg)
+	case unpublish.FullCommand():
+		return unpublishCommand(cfg)
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+func postCommand(cfg *config.Config) error {
+	date, err := composeDate()
+	if err != nil {
+		return err
+	}
+	title := strings.Join(*postTitle, " ")
+	name := date.Format("2006-01-02-") + composeSlug(title) + "." + composeOptions.ext
+	fm := composeFrontMatter(cfg, "posts", "post", title, date)
+	return writeComposedFile(cfg, "New post:", filepath.Join("_posts", name), fm, nil)
+}
+
+func draftCommand(cfg *config.Config) error {
+	title := strings.Join(*draftTitle, " ")
+	name := composeSlug(title) + "." + composeOptions.ext
+	fm := composeFrontMatter(cfg, "drafts", "post", title, time.Time{})
+	return writeComposedFile(cfg, "New draft:", filepath.Join("_drafts", name), fm, nil)
+}
+
+func pageCommand(cfg *config.Config) error {
+	title := strings.Join(*pageTitle, " ")
+	name := composeSlug(title) + "." + composeOptions.ext
+	fm := composeFrontMatter(cfg, "pages", "page", title, time.Time{})
+	return writeComposedFile(cfg, "New page:", name, fm, nil)
+}
+
+// publishCommand moves a draft from a _drafts directory to the _posts
+// directory beside it, adds the date to its filename, and sets its date
+// front matter.
+func publishCommand(cfg *config.Config) error {
+	date, err := composeDate()
+	if err != nil {
+		return err
+	}
+	return moveComposedFile(cfg, *publishPath, "_drafts", "_posts", "Published:", func(name string, fm yaml.MapSlice) (string, yaml.MapSlice, error) {
+		if _, _, found := utils.ParseFilenameDateTitle(name); found {
+			name = name[len("2006-01-02-"):]
+		}
+		fm = setMapSliceItem(fm, "date", date.Format(composeDateFormat))
+		return date.Format("2006-01-02-") + name, fm, nil
+	})
+}
+
+// unpublishCommand moves a post from a _posts directory to the _drafts
+// directory beside it, and removes the date from its filename.
+func unpublishCommand(cfg *config.Config) error {
+	return moveComposedFile(cfg, *unpublishPath, "_posts", "_drafts", "Unpublished:", func(name string, fm yaml.MapSlice) (string, yaml.MapSlice, error) {
+		if _, _, found := utils.ParseFilenameDateTitle(name); !found {
+			return "", nil, fmt.Errorf("%s: the filename doesn't begin with a date", name)
+		}
+		return name[len("2006-01-02-"):], fm, nil
+	})
+}
+
+// composeDate returns the --date option, or the current time.
+func composeDate() (time.Time, error) {
+	if composeOptions.date == "" {
+		return time.Now(), nil
+	}
+	t, err := evaluator.ParseDate(composeOptions.date)
+	if err != nil {
+		return t, fmt.Errorf("can't parse date %q", composeOptions.date)
+	}
+	return t, nil
+}
+
+// composeSlug returns the filename slug for a title. This is the inverse of
+// the title that utils.ParseFilenameDateTitle reads from a post filename.
+func composeSlug(title string) string {
+	return strings.Trim(utils.Slugify(title), "-")
+}
+
+// composeFrontMatter returns the front matter for a new file of the kind
+// (posts, drafts, or pages): its layout, title and date, and then the
+// compose default front matter for that kind. The default front matter can
+// set the layout, unless it's set on the command line.
+func composeFrontMatter(cfg *config.Config, kind, layout, title string, date time.Time) yaml.MapSlice {
+	if composeOptions.layout != "" {
+		layout = composeOptions.layout
+	}
+	fm := yaml.MapSlice{{Key: "layout", Value: layout}, {Key: "title", Value: title}}
+	if !date.IsZero() {
+		fm = append(fm, yaml.MapItem{Key: "date", Value: date.Format(composeDateFormat)})
+	}
+	defaults := cfg.Compose.DefaultFrontMatter[kind]
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch {
+		case k == "title", k == "date", k == "layout" && composeOptions.layout != "":
+			continue
+		}
+		fm = setMapSliceItem(fm, k, defaults[k])
+	}
+	return fm
+}
+
+// setMapSliceItem sets the value of a key, or appends it if it isn't present.
+func setMapSliceItem(m yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i, item := range m {
+		if item.Key == key {
+			m[i].Value = value
+			return m
+		}
+	}
+	return append(m, yaml.MapItem{Key: key, Value: value})
+}
+
+// writeComposedFile creates a file in the site source directory.
+func writeComposedFile(cfg *config.Config, label, rel string, fm yaml.MapSlice, body []byte) error {
+	b, err := frontmatter.Join(fm, body)
+	if err != nil {
+		return err
+	}
+	filename := filepath.Join(cfg.SourceDir(), rel)
+	if err := checkComposeTarget(filename); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, b, 0644); err != nil {
+		return err
+	}
+	logger.label(label, "created %s", rel)
+	return nil
+}
+
+// moveComposedFile moves a file from a fromDir directory to the toDir
+// directory beside it, after applying fn to its name and front matter.
+func moveComposedFile(cfg *config.Config, from, fromDir, toDir, label string, fn func(string, yaml.MapSlice) (string, yaml.MapSlice, error)) error {
+	filename := from
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		filename = filepath.Join(cfg.SourceDir(), from)
+	}
+	dir, name := filepath.Split(filepath.Clean(filename))
+	if filepath.Base(dir) != fromDir {
+		return fmt.Errorf("%s: not in a %s directory", from, fromDir)
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	fm, body, err := frontmatter.Split(b)
+	if err != nil {
+		return utils.WrapPathError(err, filename)
+	}
+	name, fm, err = fn(name, fm)
+	if err != nil {
+		return err
+	}
+	if b, err = frontmatter.Join(fm, body); err != nil {
+		return err
+	}
+	target := filepath.Join(filepath.Dir(filepath.Clean(dir)), toDir, name)
+	if err := checkComposeTarget(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(target, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil {
+		return err
+	}
+	logger.label(label, "moved %s to %s", from, utils.MustRel(cfg.SourceDir(), target))
+	return nil
+}
+
+// checkComposeTarget returns an error if the file exists, unless --force is set.
+func checkComposeTarget(filename string) error {
+	if _, err := os.Stat(filename); err == nil && !composeOptions.force {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", filename)
+	}
+	return nil
+}
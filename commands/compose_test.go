@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSiteAndCompose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gojekyll-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	dir = filepath.Join(dir, "site")
+
+	run := func(args ...string) error {
+		// kingpin appends to the values of repeatable arguments
+		*postTitle, *draftTitle, *pageTitle = nil, nil, nil
+		return ParseAndRun(append(args, "-q"))
+	}
+	read := func(rel string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, rel))
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	require.NoError(t, run("new", dir))
+	require.Error(t, run("new", dir))
+	config := read("_config.yml") + "compose:\n  default_front_matter:\n    posts:\n      tags: []\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "_config.yml"), []byte(config), 0644))
+
+	require.NoError(t, run("post", "-s", dir, "Hello,", "World", "--date", "2017-07-01"))
+	require.Contains(t, read("_posts/2017-07-01-hello-world.md"), "layout: post\ntitle: Hello, World\ndate: 2017-07-01 00:00")
+	require.Contains(t, read("_posts/2017-07-01-hello-world.md"), "tags: []\n")
+	require.Error(t, run("post", "-s", dir, "Hello,", "World", "--date", "2017-07-01"))
+
+	require.NoError(t, run("draft", "-s", dir, "A Draft"))
+	require.Equal(t, "---\nlayout: post\ntitle: A Draft\n---\n", read("_drafts/a-draft.md"))
+
+	require.NoError(t, run("page", "-s", dir, "Contact", "Us", "-x", "html", "-l", "default"))
+	require.Equal(t, "---\nlayout: default\ntitle: Contact Us\n---\n", read("contact-us.html"))
+
+	require.NoError(t, run("publish", "-s", dir, "_drafts/a-draft.md", "--date", "2017-07-02"))
+	require.Contains(t, read("_posts/2017-07-02-a-draft.md"), "date: 2017-07-02 00:00")
+	_, err = os.Stat(filepath.Join(dir, "_drafts/a-draft.md"))
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, run("unpublish", "-s", dir, "_posts/2017-07-02-a-draft.md"))
+	require.Contains(t, read("_drafts/a-draft.md"), "title: A Draft")
+
+	require.NoError(t, run("build", "-s", dir))
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/osteele/gojekyll/site"
+)
+
+var doctor = app.Command("doctor", "Search the site for common problems")
+
+func doctorCommand(site *site.Site) error {
+	problems, err := site.Diagnose()
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, "Warning:", p)
+	}
+	if n := len(problems); n > 0 {
+		inflect := map[bool]string{true: "", false: "s"}[n == 1]
+		return fmt.Errorf("found %d problem%s", n, inflect)
+	}
+	logger.label("Doctor:", "everything looks fine")
+	return nil
+}
@@ -16,6 +16,7 @@ var (
 	app         = kingpin.New("gojekyll", "a (somewhat) Jekyll-compatible blog generator")
 	source      = app.Flag("source", "Source directory").Short('s').Default(".").ExistingDir()
 	_           = app.Flag("destination", "Destination directory").Short('d').Action(stringVar("destination", &options.Destination)).String()
+	_           = app.Flag("environment", "Build environment; sets jekyll.environment and reads _config.ENV.yml").Action(stringVar("environment", &options.Environment)).String()
 	_           = app.Flag("drafts", "Render posts in the _drafts folder").Short('D').Action(boolVar("drafts", &options.Drafts)).Bool()
 	_           = app.Flag("future", "Publishes posts with a future date").Action(boolVar("future", &options.Future)).Bool()
 	_           = app.Flag("unpublished", "Render posts that were marked as unpublished").Action(boolVar("unpublished", &options.Unpublished)).Bool()
@@ -31,6 +32,7 @@ func init() {
 	// these flags are just present on build and serve, but I don't see a DRY way to say this
 	app.Flag("incremental", "Enable incremental rebuild.").Short('I').Action(boolVar("incremental", &options.Incremental)).Bool()
 	app.Flag("force_polling", "Force watch to use polling").BoolVar(&options.ForcePolling)
+	app.Flag("strict", "Treat route collisions as errors").BoolVar(&options.Strict)
 
 	// --watch has different defaults for build and serve
 	watchText := "Watch for changes and rebuild"
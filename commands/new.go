@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var newSite = app.Command("new", "Create a new site")
+var newSitePath = newSite.Arg("PATH", "Site directory").Required().String()
+var newSiteForce = newSite.Flag("force", "Create the site even if the directory isn't empty").Short('f').Bool()
+
+// newSiteFiles are the files of a new site, keyed by relative path.
+// In the path, DATE is replaced by the current date.
+var newSiteFiles = map[string]string{
+	".gitignore": "_site/\n",
+	"_config.yml": `title: My Site
+description: >-
+  Write a description for your new site here. It appears in the feed.
+baseurl: "" # the subpath of your site, e.g. /blog
+url: "" # the base hostname and protocol for your site, e.g. https://example.com
+plugins:
+  - jekyll-feed
+  - jekyll-seo-tag
+`,
+	"_layouts/default.html": `<!DOCTYPE html>
+<html lang="{{ site.lang | default: "en" }}">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  {% seo %}
+  {% feed_meta %}
+</head>
+<body>
+  <header>
+    <a href="{{ "/" | relative_url }}">{{ site.title }}</a>
+    <nav><a href="{{ "/about/" | relative_url }}">About</a></nav>
+  </header>
+  <main>
+    {{ content }}
+  </main>
+</body>
+</html>
+`,
+	"_layouts/page.html": `---
+layout: default
+---
+<article>
+  <h1>{{ page.title }}</h1>
+  {{ content }}
+</article>
+`,
+	"_layouts/post.html": `---
+layout: default
+---
+<article>
+  <h1>{{ page.title }}</h1>
+  <p><time datetime="{{ page.date | date_to_xmlschema }}">{{ page.date | date: "%b %-d, %Y" }}</time></p>
+  {{ content }}
+</article>
+`,
+	"index.html": `---
+layout: default
+---
+<h1>Posts</h1>
+<ul>
+  {% for post in site.posts %}
+  <li><a href="{{ post.url | relative_url }}">{{ post.title }}</a> {{ post.date | date: "%b %-d, %Y" }}</li>
+  {% endfor %}
+</ul>
+<p><a href="{{ "/feed.xml" | relative_url }}">Subscribe</a></p>
+`,
+	"about.md": `---
+layout: page
+title: About
+permalink: /about/
+---
+This is the about page. Edit about.md to change it.
+`,
+	"_posts/DATE-welcome.md": `---
+layout: post
+title: Welcome
+---
+This is your first post. Edit or remove it, and use ` + "`gojekyll post TITLE`" + ` to create more.
+Run ` + "`gojekyll serve`" + ` to preview the site.
+`,
+}
+
+func newSiteCommand() error {
+	dir := *newSitePath
+	if files, err := ioutil.ReadDir(dir); err == nil && len(files) > 0 && !*newSiteForce {
+		return fmt.Errorf("%s exists and isn't empty; use --force to create a site there anyway", dir)
+	}
+	var paths []string
+	for rel := range newSiteFiles {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	date := time.Now().Format("2006-01-02")
+	for _, rel := range paths {
+		filename := filepath.Join(dir, filepath.FromSlash(strings.Replace(rel, "DATE", date, 1)))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, []byte(newSiteFiles[rel]), 0644); err != nil {
+			return err
+		}
+	}
+	logger.path("New site:", dir)
+	logger.label("", "run `gojekyll serve -s %s` to preview it", dir)
+	return nil
+}
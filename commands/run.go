@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -43,6 +44,13 @@ func run(cmd string) error { // nolint: gocyclo
 		return pluginsCommand()
 	case versionCmd.FullCommand():
 		return versionCommand()
+	case newSite.FullCommand():
+		return newSiteCommand()
+	}
+	// These commands read the configuration, but not the site files
+	switch cmd {
+	case post.FullCommand(), draft.FullCommand(), page.FullCommand(), publish.FullCommand(), unpublish.FullCommand():
+		return composeCommand(cmd)
 	}
 
 	site, err := loadSite(*source, options)
@@ -55,13 +63,21 @@ func run(cmd string) error { // nolint: gocyclo
 	if err != nil {
 		return err
 	}
+	// doctor reports route collisions along with the other problems
+	if cmd != doctor.FullCommand() {
+		warnRouteCollisions(site)
+	}
 
 	// These commands run *after* the site is loaded
 	switch cmd {
 	case build.FullCommand():
 		return buildCommand(site)
+	case check.FullCommand():
+		return checkCommand(site)
 	case clean.FullCommand():
 		return cleanCommand(site)
+	case doctor.FullCommand():
+		return doctorCommand(site)
 	case render.FullCommand():
 		return renderCommand(site)
 	case routes.FullCommand():
@@ -93,6 +109,13 @@ func loadSite(source string, flags config.Flags) (*site.Site, error) {
 	return site, err
 }
 
+// warnRouteCollisions prints the site's route collisions.
+func warnRouteCollisions(site *site.Site) {
+	for _, c := range site.RouteCollisions() {
+		fmt.Fprintln(os.Stderr, "Warning:", c)
+	}
+}
+
 func setupProfiling() func() {
 	profilePath := "gojekyll.prof"
 	logger.label("Profiling...", "")
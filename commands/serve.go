@@ -10,8 +10,16 @@ var (
 	open  = serve.Flag("open-url", "Launch your site in a browser").Short('o').Bool()
 	_     = serve.Flag("host", "Host to bind to").Short('H').Action(stringVar("host", &options.Host)).String()
 	_     = serve.Flag("port", "Port to listen on").Short('P').Action(intVar("port", &options.Port)).Int()
+	_     = serve.Flag("livereload-port", "Port for LiveReload to listen on").Action(intVar("livereload-port", &options.LiveReloadPort)).Int()
+	_     = serve.Flag("livereload-host", "Host for the browser to connect to LiveReload; defaults to the page's host").Action(stringVar("livereload-host", &options.LiveReloadHost)).String()
+	_     = serve.Flag("ssl-cert", "X.509 (SSL) certificate file, relative to the source directory").Action(stringVar("ssl-cert", &options.SSLCert)).String()
+	_     = serve.Flag("ssl-key", "X.509 (SSL) private key file, relative to the source directory").Action(stringVar("ssl-key", &options.SSLKey)).String()
 )
 
+func init() {
+	serve.Flag("ssl-self-signed", "Serve HTTPS with a generated, self-signed certificate").BoolVar(&options.SSLSelfSigned)
+}
+
 func serveCommand(site *site.Site) error {
 	server := server.Server{Site: site}
 	return server.Run(*open, func(label, value string) {
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/k0kubun/pp"
@@ -15,8 +17,12 @@ var variables = app.Command(
 ).Alias("v").Alias("var").Alias("vars")
 
 var variablePath = variables.Arg("PATH", `Filename, URL, "site", or e.g. "site.x.y"`).String()
+var variableDefaults = variables.Flag("defaults", "Print the front matter defaults that apply to PATH, and the rules that set them").Bool()
 
 func variablesCommand(site *site.Site) (err error) {
+	if *variableDefaults {
+		return frontMatterDefaultsCommand(site)
+	}
 	var data interface{}
 	switch {
 	case strings.HasPrefix(*variablePath, "site"):
@@ -39,6 +45,31 @@ func variablesCommand(site *site.Site) (err error) {
 	return err
 }
 
+func frontMatterDefaultsCommand(site *site.Site) error {
+	d, err := pageFromPathOrRoute(site, *variablePath)
+	if err != nil {
+		return err
+	}
+	var (
+		cfg           = site.Config()
+		typename, rel = site.FrontMatterDefaultsScope(d)
+		values        = cfg.GetFrontMatterDefaults(typename, rel)
+		sources       = cfg.FrontMatterDefaultSources(typename, rel)
+		keys          = make([]string, 0, len(values))
+	)
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	logger.label("Defaults:", "%s (type %q)", rel, typename)
+	for _, k := range keys {
+		i := sources[k]
+		scope := cfg.Defaults[i].Scope
+		fmt.Printf("  %s: %v\n    from defaults[%d] (path: %q, type: %q)\n", k, values[k], i, scope.Path, scope.Type)
+	}
+	return nil
+}
+
 // modifies its argument
 func bytesToStrings(data interface{}) {
 	if m, ok := data.(map[string]interface{}); ok {
@@ -1,12 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/osteele/gojekyll/templates"
 	"github.com/osteele/gojekyll/utils"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -46,10 +46,21 @@ type Config struct {
 	}
 
 	// Serving
-	Host        string
-	Port        int
-	AbsoluteURL string `yaml:"url"`
-	BaseURL     string
+	Host           string
+	Port           int
+	LiveReloadHost string `yaml:"livereload_host"` // the host that browsers connect to; defaults to the page's host
+	LiveReloadPort int    `yaml:"livereload_port"`
+	SSLCert        string `yaml:"ssl_cert"` // relative to the source directory
+	SSLKey         string `yaml:"ssl_key"`  // relative to the source directory
+	AbsoluteURL    string `yaml:"url"`
+	BaseURL        string
+	Serve          struct {
+		Proxy map[string]ProxyRule // keyed by path prefix
+	}
+	Webrick struct {
+		Headers map[string]string // added to every response
+	}
+	MimeTypes map[string]string `yaml:"mime_types"` // file extension -> content type, for serve
 
 	// Outputting
 	Permalink string
@@ -63,15 +74,46 @@ type Config struct {
 		Values map[string]interface{}
 	}
 
+	// Reading
+	Taxonomies []string // front matter variables, besides categories and tags, that index posts
+
+	// Generating pages
+	DataPages []DataPages `yaml:"data_pages"` // pages generated from data file records
+
+	// Search index, for the gojekyll-search plugin
+	Search struct {
+		Path      string   // defaults to /search.json
+		Formats   []string // json and/or lunr; defaults to json
+		Fields    []string // url, title, headings, content, and front matter variables
+		Exclude   []string // URL patterns
+		ShardSize int      `yaml:"shard_size"` // documents per index file; 0 for one file
+	}
+
+	// Authoring, for the post, draft, and page commands
+	Compose struct {
+		DefaultFrontMatter map[string]map[string]interface{} `yaml:"default_front_matter"` // keyed by posts, drafts, or pages
+	}
+
+	// Multilingual sites
+	Languages   []string
+	DefaultLang string `yaml:"default_lang"` // defaults to the first language
+
+	// Environment
+	EnvVariables []string `yaml:"env_variables"` // names of environment variables exposed as site.env
+
 	// CLI-only
-	DryRun       bool `yaml:"-"`
-	ForcePolling bool `yaml:"-"`
-	Watch        bool `yaml:"-"`
+	DryRun        bool   `yaml:"-"`
+	Environment   string `yaml:"-"`
+	ForcePolling  bool   `yaml:"-"`
+	SSLSelfSigned bool   `yaml:"-"` // serve HTTPS with a generated certificate
+	Strict        bool   `yaml:"-"` // report problems such as route collisions as errors
+	Watch         bool   `yaml:"-"`
 
 	// Meta
-	ConfigFile string                 `yaml:"-"`
-	m          map[string]interface{} `yaml:"-"` // config file, as map
-	ms         yaml.MapSlice          `yaml:"-"` // config file, as MapSlice
+	ConfigFile            string                 `yaml:"-"`
+	EnvironmentConfigFile string                 `yaml:"-"` // _config.<env>.yml, if present
+	m                     map[string]interface{} `yaml:"-"` // config file, as map
+	ms                    yaml.MapSlice          `yaml:"-"` // config file, as MapSlice
 
 	// Plugins
 	RequireFrontMatter        bool            `yaml:"-"`
@@ -94,10 +136,49 @@ func (c *Config) FromDirectory(dir string) error {
 		}
 		c.ConfigFile = path
 	}
+	if err := c.readEnvironmentConfig(dir); err != nil {
+		return err
+	}
 	c.Source = dir
 	return nil
 }
 
+// readEnvironmentConfig merges _config.<env>.yml, if it exists, on top of
+// the configuration.
+func (c *Config) readEnvironmentConfig(dir string) error {
+	path := filepath.Join(dir, c.environmentConfigPath())
+	bytes, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	if err = c.merge(bytes); err != nil {
+		return utils.WrapPathError(err, path)
+	}
+	c.EnvironmentConfigFile = path
+	return nil
+}
+
+// environmentConfigPath returns the relative path of the configuration overlay
+// for the current environment.
+func (c *Config) environmentConfigPath() string {
+	return fmt.Sprintf("_config.%s.yml", c.Env())
+}
+
+// Env returns the build environment. This is the --environment flag if it was
+// set, else the value of JEKYLL_ENV, else "development".
+func (c *Config) Env() string {
+	if c.Environment != "" {
+		return c.Environment
+	}
+	if env := os.Getenv("JEKYLL_ENV"); env != "" {
+		return env
+	}
+	return "development"
+}
+
 type configCompat struct {
 	Gems []string
 }
@@ -112,7 +193,7 @@ type collectionsMap struct {
 
 // IsConfigPath returns true if its arguments is a site configuration file.
 func (c *Config) IsConfigPath(rel string) bool {
-	return rel == "_config.yml"
+	return rel == "_config.yml" || rel == c.environmentConfigPath()
 }
 
 // SassDir returns the relative path of the SASS directory.
@@ -125,19 +206,6 @@ func (c *Config) SourceDir() string {
 	return utils.MustAbs(c.Source)
 }
 
-// GetFrontMatterDefaults implements https://jekyllrb.com/docs/configuration/#front-matter-defaults
-func (c *Config) GetFrontMatterDefaults(typename, rel string) (m map[string]interface{}) {
-	for _, entry := range c.Defaults {
-		scope := &entry.Scope
-		hasPrefix := strings.HasPrefix(rel, scope.Path)
-		hasType := scope.Type == "" || scope.Type == typename
-		if hasPrefix && hasType {
-			m = templates.MergeVariableMaps(m, entry.Values)
-		}
-	}
-	return
-}
-
 // RequiresFrontMatter returns a bool indicating whether the file requires front matter in order to recognize as a page.
 func (c *Config) RequiresFrontMatter(rel string) bool {
 	switch {
@@ -190,6 +258,23 @@ func Unmarshal(bytes []byte, c *Config) error {
 	return nil
 }
 
+// merge updates the configuration from a YAML configuration overlay.
+// Unlike Unmarshal, it keeps the variables that the overlay doesn't set.
+func (c *Config) merge(bytes []byte) error {
+	ms := c.ms
+	if err := Unmarshal(bytes, c); err != nil {
+		return err
+	}
+	overlay := c.ms
+	c.ms = ms
+	for _, item := range overlay {
+		if k, ok := item.Key.(string); ok {
+			c.Set(k, item.Value)
+		}
+	}
+	return nil
+}
+
 // Variables returns the configuration as a Liquid variable map.
 func (c *Config) Variables() map[string]interface{} {
 	m := map[string]interface{}{}
@@ -205,9 +290,9 @@ func (c *Config) Variables() map[string]interface{} {
 // This does not update the corresponding value in the Config struct.
 func (c *Config) Set(key string, val interface{}) {
 	c.m[key] = val
-	for _, item := range c.ms {
+	for i, item := range c.ms {
 		if item.Key == key {
-			item.Value = val
+			c.ms[i].Value = val
 			return
 		}
 	}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -45,3 +46,38 @@ func TestConfig_IsMarkdown(t *testing.T) {
 	require.True(t, c.IsMarkdown("name.markdown"))
 	require.False(t, c.IsMarkdown("name.html"))
 }
+
+func TestConfig_Env(t *testing.T) {
+	c := Default()
+	os.Setenv("JEKYLL_ENV", "") // nolint: errcheck
+	require.Equal(t, "development", c.Env())
+	require.True(t, c.IsConfigPath("_config.development.yml"))
+
+	os.Setenv("JEKYLL_ENV", "production") // nolint: errcheck
+	require.Equal(t, "production", c.Env())
+
+	c.Environment = "staging"
+	require.Equal(t, "staging", c.Env())
+	require.True(t, c.IsConfigPath("_config.staging.yml"))
+	require.False(t, c.IsConfigPath("_config.production.yml"))
+	os.Setenv("JEKYLL_ENV", "") // nolint: errcheck
+}
+
+func TestConfig_merge(t *testing.T) {
+	c := Default()
+	require.NoError(t, Unmarshal([]byte("title: t\nurl: http://localhost"), &c))
+	require.NoError(t, c.merge([]byte("url: https://example.com\nenv_variables: [API_URL]")))
+	require.Equal(t, "https://example.com", c.AbsoluteURL)
+	require.Equal(t, []string{"API_URL"}, c.EnvVariables)
+	v := c.Variables()
+	require.Equal(t, "t", v["title"])
+	require.Equal(t, "https://example.com", v["url"])
+}
+
+func TestUnmarshal_proxy(t *testing.T) {
+	c := Default()
+	src := "serve:\n  proxy:\n    /api/: http://localhost:8080\n    /ws/:\n      target: http://localhost:9000\n      strip_prefix: true\n      headers:\n        X-Dev: yes\n"
+	require.NoError(t, Unmarshal([]byte(src), &c))
+	require.Equal(t, ProxyRule{Target: "http://localhost:8080"}, c.Serve.Proxy["/api/"])
+	require.Equal(t, ProxyRule{Target: "http://localhost:9000", StripPrefix: true, Headers: map[string]string{"X-Dev": "yes"}}, c.Serve.Proxy["/ws/"])
+}
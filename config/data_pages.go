@@ -0,0 +1,43 @@
+package config
+
+import "strings"
+
+// DataPages configures a page for each record of a data file. For example,
+//
+//	data_pages:
+//	  - data: products
+//	    layout: product
+//	    slug: sku
+//	    permalink: /products/:slug/
+//	    name: product
+//
+// generates a page at /products/SKU/ for each record in _data/products.yml,
+// that renders the product layout with the record in page.product.
+type DataPages struct {
+	Data      string // the data, as a dotted path into site.data; for example, catalog.products
+	Layout    string
+	Slug      string // the record field that names the page; defaults to "slug"
+	Permalink string // defaults to the site's permalink for a page at DATA/SLUG.html
+	Name      string // the page variable that holds the record; defaults to "record"
+}
+
+// DataPath returns the path of the data in site.data, as a list of keys.
+func (d DataPages) DataPath() []string {
+	return strings.Split(d.Data, ".")
+}
+
+// SlugField returns the name of the record field that names a page.
+func (d DataPages) SlugField() string {
+	if d.Slug == "" {
+		return "slug"
+	}
+	return d.Slug
+}
+
+// VariableName returns the name of the page variable that holds the record.
+func (d DataPages) VariableName() string {
+	if d.Name == "" {
+		return "record"
+	}
+	return d.Name
+}
@@ -52,6 +52,7 @@ incremental: false
 detach:  false
 port:    4000
 host:    127.0.0.1
+livereload_port: 35729
 baseurl: "" # does not include hostname
 
 # Outputting
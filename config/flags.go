@@ -9,13 +9,14 @@ import (
 type Flags struct {
 	// these are pointers so we can tell whether they've been set, and leave
 	// the config file alone if not
-	Destination, Host           *string
-	Drafts, Future, Unpublished *bool
-	Incremental, Verbose        *bool
-	Port                        *int
+	Destination, Environment, Host  *string
+	LiveReloadHost, SSLCert, SSLKey *string
+	Drafts, Future, Unpublished     *bool
+	Incremental, Verbose            *bool
+	Port, LiveReloadPort            *int
 
 	// these aren't in the config file, so make them actual values
-	DryRun, ForcePolling, Watch bool
+	DryRun, ForcePolling, SSLSelfSigned, Strict, Watch bool
 }
 
 // ApplyFlags overwrites the configuration with values from flags.
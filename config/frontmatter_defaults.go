@@ -0,0 +1,103 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// Front matter defaults scope types that aren't collection names.
+const (
+	PagesScopeType  = "pages"
+	DraftsScopeType = "drafts"
+	postsScopeType  = "posts"
+)
+
+// GetFrontMatterDefaults implements https://jekyllrb.com/docs/configuration/#front-matter-defaults
+//
+// typename is "pages", "drafts", or a collection name; or the empty string
+// for a static file outside a collection. rel is the site-relative path.
+func (c *Config) GetFrontMatterDefaults(typename, rel string) map[string]interface{} {
+	m, _ := c.frontMatterDefaults(typename, rel)
+	return m
+}
+
+// FrontMatterDefaultSources returns a map from each variable that the
+// front matter defaults set for a file, to the index in c.Defaults of the
+// entry that supplied its value.
+func (c *Config) FrontMatterDefaultSources(typename, rel string) map[string]int {
+	_, sources := c.frontMatterDefaults(typename, rel)
+	return sources
+}
+
+// frontMatterDefaults merges the applicable defaults entries. As in Jekyll,
+// an entry with a longer scope path takes precedence over one with a
+// shorter path; between entries with the same path, one with a type takes
+// precedence over one without. Otherwise later entries take precedence.
+func (c *Config) frontMatterDefaults(typename, rel string) (m map[string]interface{}, sources map[string]int) {
+	m, sources = map[string]interface{}{}, map[string]int{}
+	rel = strings.Trim(path.Clean("/"+strings.Replace(rel, "\\", "/", -1)), "/")
+	prev := -1
+	for i, entry := range c.Defaults {
+		scope := &entry.Scope
+		if !scopeMatchesType(scope.Type, typename) || !scopeMatchesPath(scope.Path, rel) {
+			continue
+		}
+		precedes := prev < 0 || c.scopePrecedes(i, prev)
+		for k, v := range entry.Values {
+			if _, found := m[k]; !found || precedes {
+				m[k] = v
+				sources[k] = i
+			}
+		}
+		if precedes {
+			prev = i
+		}
+	}
+	return m, sources
+}
+
+// scopePrecedes returns true if the scope of entry i takes precedence over that of entry j.
+func (c *Config) scopePrecedes(i, j int) bool {
+	a, b := &c.Defaults[i].Scope, &c.Defaults[j].Scope
+	ap, bp := strings.Trim(a.Path, "/"), strings.Trim(b.Path, "/")
+	switch {
+	case len(ap) != len(bp):
+		return len(ap) > len(bp)
+	case a.Type != "":
+		return true
+	default:
+		return b.Type == ""
+	}
+}
+
+func scopeMatchesType(scopeType, typename string) bool {
+	switch scopeType {
+	case "", typename:
+		return true
+	case postsScopeType:
+		// drafts are posts too
+		return typename == DraftsScopeType
+	default:
+		return false
+	}
+}
+
+// scopeMatchesPath returns true if rel, or a directory that contains it,
+// is the scope path or matches the scope path's glob pattern.
+func scopeMatchesPath(scopePath, rel string) bool {
+	scopePath = strings.Trim(scopePath, "/")
+	if scopePath == "" || scopePath == "." {
+		return true
+	}
+	glob := strings.ContainsAny(scopePath, "*?[")
+	for p := rel; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if glob {
+			if match, err := path.Match(scopePath, p); err == nil && match {
+				return true
+			}
+		} else if p == scopePath {
+			return true
+		}
+	}
+	return false
+}
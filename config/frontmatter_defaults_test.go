@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const defaultsConfig = `
+defaults:
+  - scope:
+      path: ""
+    values:
+      layout: default
+      author: site
+  - scope:
+      path: "docs"
+      type: pages
+    values:
+      layout: doc
+  - scope:
+      path: "docs/*/assets"
+    values:
+      image: true
+  - scope:
+      type: posts
+    values:
+      layout: post
+  - scope:
+      type: drafts
+    values:
+      author: draft
+  - scope:
+      path: ""
+    values:
+      author: later
+`
+
+func TestConfig_GetFrontMatterDefaults(t *testing.T) {
+	c := Default()
+	require.NoError(t, Unmarshal([]byte(defaultsConfig), &c))
+
+	m := c.GetFrontMatterDefaults(PagesScopeType, "index.md")
+	require.Equal(t, "default", m["layout"])
+	require.Equal(t, "later", m["author"])
+
+	m = c.GetFrontMatterDefaults(PagesScopeType, "docs/index.md")
+	require.Equal(t, "doc", m["layout"])
+
+	// path scopes match whole path segments
+	m = c.GetFrontMatterDefaults(PagesScopeType, "docs2/index.md")
+	require.Equal(t, "default", m["layout"])
+
+	// type scopes
+	m = c.GetFrontMatterDefaults("", "docs/image.png")
+	require.Equal(t, "default", m["layout"])
+	// a typed scope takes precedence over a later untyped scope with the same path
+	m = c.GetFrontMatterDefaults("posts", "_posts/2017-01-01-post.md")
+	require.Equal(t, "post", m["layout"])
+	require.Equal(t, "site", m["author"])
+	m = c.GetFrontMatterDefaults(DraftsScopeType, "_drafts/draft.md")
+	require.Equal(t, "post", m["layout"])
+	require.Equal(t, "draft", m["author"])
+
+	// glob scopes
+	m = c.GetFrontMatterDefaults("", "docs/v1/assets/logo.png")
+	require.Equal(t, true, m["image"])
+	m = c.GetFrontMatterDefaults("", "docs/v1/logo.png")
+	require.Nil(t, m["image"])
+}
+
+func TestConfig_FrontMatterDefaultSources(t *testing.T) {
+	c := Default()
+	require.NoError(t, Unmarshal([]byte(defaultsConfig), &c))
+
+	sources := c.FrontMatterDefaultSources(PagesScopeType, "docs/index.md")
+	require.Equal(t, 1, sources["layout"])
+	require.Equal(t, 0, sources["author"])
+
+	sources = c.FrontMatterDefaultSources(DraftsScopeType, "_drafts/draft.md")
+	require.Equal(t, 3, sources["layout"])
+	require.Equal(t, 4, sources["author"])
+
+	sources = c.FrontMatterDefaultSources(PagesScopeType, "index.md")
+	require.Equal(t, 5, sources["author"])
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// deprecatedKeys maps configuration keys that Jekyll has deprecated or
+// removed to advice about what to use instead.
+var deprecatedKeys = map[string]string{
+	"auto":                "use the --watch flag instead",
+	"gems":                "use plugins instead",
+	"pygments":            "use highlighter: pygments instead",
+	"relative_permalinks": "relative permalinks are no longer supported",
+	"server":              "use the serve command instead",
+	"server_port":         "use port instead",
+	"watch":               "use the --watch flag instead",
+}
+
+// unsupportedKeys are Jekyll configuration keys that gojekyll ignores.
+var unsupportedKeys = []string{
+	"highlighter",
+	"kramdown",
+	"limit_posts",
+	"liquid",
+	"lsi",
+	"markdown",
+	"rdiscount",
+	"redcarpet",
+	"safe",
+	"show_dir_listing",
+}
+
+// KeyWarnings returns warnings about the keys of the configuration file:
+// keys that Jekyll has deprecated, Jekyll keys that gojekyll doesn't
+// support, and keys that look like misspellings of the keys that it does.
+//
+// Other keys aren't reported, since a site can use any key as a site variable.
+func (c *Config) KeyWarnings() (warnings []string) {
+	var (
+		known       = knownKeys()
+		unsupported = map[string]bool{}
+	)
+	for _, k := range unsupportedKeys {
+		unsupported[k] = true
+	}
+	for _, item := range c.ms {
+		key, ok := item.Key.(string)
+		switch {
+		case !ok, known[key]:
+		case deprecatedKeys[key] != "":
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated: %s", key, deprecatedKeys[key]))
+		case unsupported[key]:
+			warnings = append(warnings, fmt.Sprintf("%s is not supported by gojekyll", key))
+		default:
+			if k := closestKey(key, known); k != "" {
+				warnings = append(warnings, fmt.Sprintf("%s is not a configuration key; did you mean %s?", key, k))
+			}
+		}
+	}
+	return
+}
+
+// knownKeys returns the keys that gojekyll reads: the YAML names of the
+// Config fields, and the keys of the default configuration.
+func knownKeys() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		switch {
+		case f.PkgPath != "", name == "-":
+			continue
+		case name == "":
+			name = strings.ToLower(f.Name)
+		}
+		keys[name] = true
+	}
+	var defaults yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(defaultSiteConfig), &defaults); err != nil {
+		panic(err)
+	}
+	for _, item := range defaults {
+		keys[item.Key.(string)] = true
+	}
+	return keys
+}
+
+// closestKey returns the known key that key is probably a misspelling of,
+// or "" if there isn't one.
+func closestKey(key string, known map[string]bool) string {
+	if len(key) < 5 {
+		return ""
+	}
+	var candidates []string
+	for k := range known {
+		candidates = append(candidates, k)
+	}
+	sort.Strings(candidates)
+	maxDistance := 1
+	if len(key) > 6 {
+		maxDistance = 2
+	}
+	best, bestDistance := "", maxDistance+1
+	for _, k := range candidates {
+		if d := editDistance(strings.ToLower(key), k); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		row := make([]int, len(b)+1)
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min3(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+		}
+		prev = row
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_KeyWarnings(t *testing.T) {
+	c := Default()
+	require.NoError(t, Unmarshal([]byte(`
+title: My Site
+baseurl: /blog
+exlude: [README.md]
+permalinks: pretty
+gems: [jekyll-feed]
+lsi: true
+`), &c))
+	require.Equal(t, []string{
+		"exlude is not a configuration key; did you mean exclude?",
+		"permalinks is not a configuration key; did you mean permalink?",
+		"gems is deprecated: use plugins instead",
+		"lsi is not supported by gojekyll",
+	}, c.KeyWarnings())
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("exclude", "exclude"))
+	require.Equal(t, 1, editDistance("exlude", "exclude"))
+	require.Equal(t, 3, editDistance("kitten", "sitting"))
+}
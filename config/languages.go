@@ -0,0 +1,52 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// I18nDir is the directory that holds the translated strings of a
+// multilingual site, in files such as _i18n/fr.yml.
+const I18nDir = "_i18n"
+
+// IsMultilingual returns a bool indicating whether the configuration lists
+// languages.
+func (c *Config) IsMultilingual() bool {
+	return len(c.Languages) > 0
+}
+
+// DefaultLanguage returns the language whose documents are output at the
+// root of the site. This is default_lang if it's set, else the first language.
+// It returns "" if the site isn't multilingual.
+func (c *Config) DefaultLanguage() string {
+	switch {
+	case c.DefaultLang != "":
+		return c.DefaultLang
+	case len(c.Languages) > 0:
+		return c.Languages[0]
+	default:
+		return ""
+	}
+}
+
+// IsLanguage returns a bool indicating whether lang is one of the site languages.
+func (c *Config) IsLanguage(lang string) bool {
+	for _, l := range c.Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// LanguageFolder splits a slash-separated path into the language folder
+// that it begins with, and the remainder. For example, "fr/about.md" is in the
+// "fr" folder, if fr is a site language. It returns "" and the path if the
+// path isn't in a language folder.
+func (c *Config) LanguageFolder(rel string) (lang, rest string) {
+	segments := strings.SplitN(path.Clean(rel), "/", 2)
+	if len(segments) == 2 && c.IsLanguage(segments[0]) {
+		return segments[0], segments[1]
+	}
+	return "", rel
+}
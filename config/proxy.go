@@ -0,0 +1,31 @@
+package config
+
+// A ProxyRule forwards the development server's requests for a path prefix
+// to another server. For example,
+//
+//	serve:
+//	  proxy:
+//	    /api/: http://localhost:8080
+//	    /socket/:
+//	      target: http://localhost:9000
+//	      strip_prefix: true
+//	      headers:
+//	        Authorization: Bearer development-token
+//
+// forwards /api/users to http://localhost:8080/api/users, and /socket/chat,
+// including websocket connections, to http://localhost:9000/chat.
+type ProxyRule struct {
+	Target          string            // the upstream URL
+	StripPrefix     bool              `yaml:"strip_prefix"` // remove the prefix from the forwarded path
+	Headers         map[string]string // request headers to set; an empty value removes the header
+	ResponseHeaders map[string]string `yaml:"response_headers"` // response headers to set; an empty value removes the header
+}
+
+// UnmarshalYAML accepts a URL string, as a shorthand for a rule with only a target.
+func (r *ProxyRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&r.Target); err == nil {
+		return nil
+	}
+	type plain ProxyRule
+	return unmarshal((*plain)(r))
+}
@@ -0,0 +1,50 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/osteele/liquid"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// AddTranslationFilter adds the t filter. This looks up a dotted key, such
+// as "nav.home", in each of the translation tables in turn; and returns the
+// key itself if none of them has it.
+//
+// A multilingual site has one rendering engine per language; its tables
+// are that language's strings, followed by the default language's.
+func AddTranslationFilter(e *liquid.Engine, tables ...interface{}) {
+	e.RegisterFilter("t", func(key string) interface{} {
+		for _, table := range tables {
+			if value, ok := lookupKeyPath(table, strings.Split(key, ".")); ok {
+				return value
+			}
+		}
+		return key
+	})
+}
+
+// lookupKeyPath looks up a key path in nested maps, as read from YAML or JSON.
+func lookupKeyPath(value interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		var found bool
+		switch m := value.(type) {
+		case map[string]interface{}:
+			value, found = m[key]
+		case map[interface{}]interface{}:
+			value, found = m[key]
+		case yaml.MapSlice:
+			for _, item := range m {
+				if fmt.Sprint(item.Key) == key {
+					value, found = item.Value, true
+					break
+				}
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,26 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/osteele/liquid"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestTranslationFilter(t *testing.T) {
+	var fr, en yaml.MapSlice
+	require.NoError(t, yaml.Unmarshal([]byte("nav:\n  home: Accueil\n"), &fr))
+	require.NoError(t, yaml.Unmarshal([]byte("nav:\n  home: Home\n  about: About\n"), &en))
+	engine := liquid.NewEngine()
+	AddTranslationFilter(engine, fr, en)
+	for src, expected := range map[string]string{
+		`{{ "nav.home" | t }}`:    "Accueil",
+		`{{ "nav.about" | t }}`:   "About",
+		`{{ "nav.missing" | t }}`: "nav.missing",
+	} {
+		out, err := engine.ParseAndRenderString(src, liquid.Bindings{})
+		require.NoError(t, err)
+		require.Equal(t, expected, out, src)
+	}
+}
@@ -27,3 +27,20 @@ func TestFrontMatter_SortedStringArray(t *testing.T) {
 	require.Equal(t, []string{"a", "b"}, sorted([]string{"b", "a"}))
 	require.Len(t, sorted(3), 0)
 }
+
+func TestSplitJoin(t *testing.T) {
+	fm, body, err := Split([]byte("---\nb: 1\na: 2\n---\n\nbody\n"))
+	require.NoError(t, err)
+	require.Len(t, fm, 2)
+	require.Equal(t, "b", fm[0].Key)
+	require.Equal(t, "body\n", string(body))
+
+	b, err := Join(fm, body)
+	require.NoError(t, err)
+	require.Equal(t, "---\nb: 1\na: 2\n---\n\nbody\n", string(b))
+
+	fm, body, err = Split([]byte("no front matter\n"))
+	require.NoError(t, err)
+	require.Nil(t, fm)
+	require.Equal(t, "no front matter\n", string(body))
+}
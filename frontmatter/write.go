@@ -0,0 +1,40 @@
+package frontmatter
+
+import (
+	"bytes"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Split separates a document into its front matter, as a MapSlice that
+// preserves the order of its keys, and its body.
+func Split(source []byte) (fm yaml.MapSlice, body []byte, err error) {
+	source = bytes.Replace(source, []byte("\r\n"), []byte("\n"), -1)
+	if match := frontMatterMatcher.FindSubmatchIndex(source); match != nil {
+		err = yaml.Unmarshal(source[match[2]:match[3]], &fm)
+		return fm, source[match[1]:], err
+	}
+	if match := emptyFontMatterMatcher.FindSubmatchIndex(source); match != nil {
+		return nil, source[match[1]:], nil
+	}
+	return nil, source, nil
+}
+
+// Join is the inverse of Split. It returns a document with front matter fm
+// and content body.
+func Join(fm yaml.MapSlice, body []byte) ([]byte, error) {
+	buf := bytes.NewBufferString(fmMagic)
+	if len(fm) > 0 {
+		b, err := yaml.Marshal(fm)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteString(fmMagic)
+	if len(body) > 0 {
+		buf.WriteString("\n")
+		buf.Write(body)
+	}
+	return buf.Bytes(), nil
+}
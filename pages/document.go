@@ -22,6 +22,13 @@ type Document interface {
 	Reload() error
 }
 
+// A Redirect is a document that sends the browser to another URL, such as a
+// page that jekyll-redirect-from creates for a redirect_from variable.
+type Redirect interface {
+	Document
+	RedirectURL() string
+}
+
 // Site is the interface that the site provides to a page.
 type Site interface {
 	Config() *config.Config
@@ -10,16 +10,20 @@ import (
 )
 
 // ToLiquid is part of the liquid.Drop interface.
+// Front matter defaults are visible, but can't override the file properties.
 func (d *StaticFile) ToLiquid() interface{} {
-	return liquid.IterationKeyedMap(map[string]interface{}{
+	drop := d.fm.Merged(frontmatter.FrontMatter{
 		"name":          path.Base(d.relPath),
 		"basename":      utils.TrimExt(path.Base(d.relPath)),
 		"path":          d.URL(),
 		"modified_time": d.modTime,
 		"extname":       d.OutputExt(),
 		// de facto:
-		"collection": nil,
+		"collection": d.fm.Get("collection", nil),
 	})
+	// the collection permalink pattern
+	delete(drop, "permalink")
+	return liquid.IterationKeyedMap(drop)
 }
 
 func (f *file) ToLiquid() interface{} {
@@ -39,6 +43,11 @@ func (f *file) ToLiquid() interface{} {
 
 // ToLiquid is in the liquid.Drop interface.
 func (p *page) ToLiquid() interface{} {
+	return liquid.IterationKeyedMap(p.liquidMap())
+}
+
+// liquidMap returns the page variables.
+func (p *page) liquidMap() map[string]interface{} {
 	var (
 		fm          = p.fm
 		relpath     = p.relPath
@@ -60,6 +69,9 @@ func (p *page) ToLiquid() interface{} {
 		// de facto
 		"ext": ext,
 	}
+	if lang := p.Lang(); lang != "" {
+		data["lang"] = lang
+	}
 	for k, v := range p.fm {
 		switch k {
 		// doc implies these aren't present, but they appear to be present in a collection page:
@@ -70,7 +82,7 @@ func (p *page) ToLiquid() interface{} {
 			data[k] = v
 		}
 	}
-	return liquid.IterationKeyedMap(data)
+	return data
 }
 
 func (p *page) maybeContent() interface{} {
@@ -26,13 +26,14 @@ func TestStaticFile_ToLiquid(t *testing.T) {
 
 func TestPage_ToLiquid_excerpt(t *testing.T) {
 	site := siteFake{t, config.Default()}
-	p, err := NewFile(site, "testdata/excerpt.md", "excerpt.md", map[string]interface{}{})
+	fm := map[string]interface{}{"collection": "posts"}
+	p, err := NewFile(site, "testdata/excerpt.md", "excerpt.md", fm)
 	require.NoError(t, err)
 
 	t.Run("before render", func(t *testing.T) {
 		drop := p.(liquid.Drop).ToLiquid()
 		excerpt := drop.(tags.IterationKeyedMap)["excerpt"]
-		require.Equal(t, "First line.", fmt.Sprintf("%s", excerpt))
+		require.Equal(t, "rendered: First line.", fmt.Sprintf("%s", excerpt))
 	})
 
 	t.Run("after render", func(t *testing.T) {
@@ -41,4 +42,35 @@ func TestPage_ToLiquid_excerpt(t *testing.T) {
 		excerpt := drop.(tags.IterationKeyedMap)["excerpt"]
 		require.Equal(t, "rendered: First line.", fmt.Sprintf("%s", excerpt))
 	})
+
+	t.Run("excerpt_separator", func(t *testing.T) {
+		fm := map[string]interface{}{"collection": "posts", "excerpt_separator": "Second"}
+		p, err := NewFile(site, "testdata/excerpt.md", "excerpt.md", fm)
+		require.NoError(t, err)
+		excerpt := p.(liquid.Drop).ToLiquid().(tags.IterationKeyedMap)["excerpt"]
+		require.Equal(t, "rendered: First line.\n\n", fmt.Sprintf("%s", excerpt))
+	})
+
+	t.Run("non-post", func(t *testing.T) {
+		p, err := NewFile(site, "testdata/excerpt.md", "excerpt.md", map[string]interface{}{})
+		require.NoError(t, err)
+		require.Nil(t, p.(liquid.Drop).ToLiquid().(tags.IterationKeyedMap)["excerpt"])
+
+		cfg := config.Default()
+		cfg.Collections = map[string]map[string]interface{}{"docs": {"excerpts": true}}
+		site := siteFake{t, cfg}
+		fm := map[string]interface{}{"collection": "docs"}
+		p, err = NewFile(site, "testdata/excerpt.md", "excerpt.md", fm)
+		require.NoError(t, err)
+		excerpt := p.(liquid.Drop).ToLiquid().(tags.IterationKeyedMap)["excerpt"]
+		require.Equal(t, "rendered: First line.", fmt.Sprintf("%s", excerpt))
+	})
+}
+
+func TestNewExcerpt_linkReferences(t *testing.T) {
+	site := siteFake{t, config.Default()}
+	f := file{site: site, fm: map[string]interface{}{"collection": "posts"}}
+	p := &page{file: f, raw: []byte("See [the docs][docs].\n\nMore.\n\n[docs]: http://example.com\n")}
+	e := newExcerpt(p)
+	require.Equal(t, "See [the docs][docs].\n\n[docs]: http://example.com", string(e.raw))
 }
@@ -0,0 +1,109 @@
+package pages
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+
+	"github.com/osteele/gojekyll/utils"
+)
+
+// An Excerpt is the part of a document's source before its excerpt separator.
+//
+// As in Jekyll, an excerpt is rendered as a document in its own right: its
+// Liquid and Markdown are processed separately from the containing document,
+// and in the containing document's template context. It is rendered the
+// first time its content is requested.
+type Excerpt struct {
+	page *page
+	raw  []byte
+
+	once    sync.Once
+	content string
+	err     error
+}
+
+// Markdown reference-style link definitions, e.g. "[id]: http://example.com".
+var linkReferenceMatcher = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:.+$`)
+
+// newExcerpt returns the excerpt of p's source, or nil if the page doesn't
+// have an excerpt.
+func newExcerpt(p *page) *Excerpt {
+	sep := p.excerptSeparator()
+	if sep == "" || !p.hasExcerpt() {
+		return nil
+	}
+	raw := p.raw
+	if pos := bytes.Index(raw, []byte(sep)); pos >= 0 {
+		raw = raw[:pos]
+		// As in Jekyll, include the link definitions from the rest of the
+		// document, so that reference-style links in the excerpt resolve.
+		if refs := linkReferenceMatcher.FindAll(p.raw[pos:], -1); len(refs) > 0 {
+			raw = append(append([]byte{}, raw...), "\n\n"...)
+			raw = append(raw, bytes.Join(refs, []byte("\n"))...)
+		}
+	}
+	return &Excerpt{page: p, raw: raw}
+}
+
+// excerptSeparator returns the page's excerpt_separator front matter
+// variable, or the site's if the page doesn't set one.
+func (p *page) excerptSeparator() string {
+	return p.fm.String("excerpt_separator", p.site.Config().ExcerptSeparator)
+}
+
+// hasExcerpt returns true if the page has an excerpt. Posts have excerpts.
+// Other collection documents have them if the collection sets "excerpts: true".
+func (p *page) hasExcerpt() bool {
+	if p.IsPost() {
+		return true
+	}
+	name := p.fm.String("collection", "")
+	if name == "" {
+		return false
+	}
+	enabled, _ := p.site.Config().Collections[name]["excerpts"].(bool)
+	return enabled
+}
+
+// Source returns the filename of the containing document.
+func (e *Excerpt) Source() string { return e.page.filename }
+
+// URL returns the URL of the containing document.
+func (e *Excerpt) URL() string { return e.page.URL() }
+
+// Content returns the rendered excerpt.
+func (e *Excerpt) Content() (string, error) {
+	e.once.Do(func() {
+		buf := new(bytes.Buffer)
+		err := e.page.renderer().Render(buf, e.raw, e.templateContext(), e.page.filename, e.page.firstLine)
+		e.content, e.err = buf.String(), utils.WrapPathError(err, e.page.filename)
+	})
+	return e.content, e.err
+}
+
+// templateContext returns the containing document's template context,
+// without page.excerpt; a template that requests the excerpt of its own
+// excerpt would otherwise render it recursively.
+func (e *Excerpt) templateContext() map[string]interface{} {
+	pageVars := e.page.liquidMap()
+	delete(pageVars, "excerpt")
+	vars := e.page.TemplateContext()
+	vars["page"] = pageVars
+	return vars
+}
+
+// ToLiquid is in the liquid.Drop interface. It returns the rendered excerpt.
+//
+// A rendering error produces an empty excerpt here. Rendering the containing
+// page renders its excerpt too, and reports the error.
+func (e *Excerpt) ToLiquid() interface{} {
+	return e.String()
+}
+
+// String returns the rendered excerpt, or an empty string if it can't be
+// rendered.
+func (e *Excerpt) String() string {
+	s, _ := e.Content()
+	return s
+}
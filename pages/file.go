@@ -24,7 +24,7 @@ type file struct {
 //
 // filename is the absolute filename. relpath is the path relative to the site or collection directory.
 func NewFile(s Site, filename string, relpath string, fm frontmatter.FrontMatter) (Document, error) {
-	hasFM, err := frontmatter.FileHasFrontMatter(filename)
+	static, err := IsStaticFile(s, filename, relpath)
 	if err != nil {
 		return nil, err
 	}
@@ -41,14 +41,30 @@ func NewFile(s Site, filename string, relpath string, fm frontmatter.FrontMatter
 		relPath:   relpath,
 		outputExt: s.Config().OutputExt(relpath),
 	}
-	if hasFM || !s.Config().RequiresFrontMatter(relpath) {
+	if !static {
 		return makePage(filename, fields)
 	}
 	fields.permalink = "/" + relpath
+	if fm.String("collection", "") != "" {
+		fields.permalink, err = fields.staticCollectionPermalink()
+		if err != nil {
+			return nil, err
+		}
+	}
 	p := &StaticFile{fields}
 	return p, nil
 }
 
+// IsStaticFile returns a bool indicating whether NewFile creates a StaticFile,
+// rather than a Page, for the file.
+func IsStaticFile(s Site, filename string, relpath string) (bool, error) {
+	hasFM, err := frontmatter.FileHasFrontMatter(filename)
+	if err != nil {
+		return false, err
+	}
+	return !hasFM && s.Config().RequiresFrontMatter(relpath), nil
+}
+
 func (f *file) String() string {
 	return fmt.Sprintf("%T{Path=%v, Permalink=%v}", f, f.relPath, f.permalink)
 }
@@ -0,0 +1,40 @@
+package pages
+
+import (
+	"time"
+
+	"github.com/osteele/gojekyll/frontmatter"
+)
+
+// NewGeneratedPage creates a Page that isn't read from a file; for example,
+// a page generated from a data file record.
+//
+// relpath is the slash-separated path of the file that the page stands in
+// for. It determines the page's output extension and permalink variables.
+// The page has no content of its own, so its output is its layout
+// applied to an empty string.
+func NewGeneratedPage(s Site, relpath string, fm frontmatter.FrontMatter) (Page, error) {
+	fields := file{
+		site:      s,
+		dfm:       fm,
+		fm:        fm,
+		modTime:   time.Now(),
+		relPath:   relpath,
+		outputExt: s.Config().OutputExt(relpath),
+	}
+	p := &generatedPage{page{file: fields, firstLine: 1}}
+	if err := p.setPermalink(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// A generatedPage is a page without a source file.
+type generatedPage struct{ page }
+
+// Reload is in the Document interface. A generated page is regenerated,
+// rather than reloaded, when its data changes.
+func (p *generatedPage) Reload() error {
+	p.reset()
+	return nil
+}
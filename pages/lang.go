@@ -0,0 +1,56 @@
+package pages
+
+import (
+	"strings"
+
+	"github.com/osteele/gojekyll/renderers"
+)
+
+// Lang is in the Page interface.
+//
+// A document's language is its lang front matter variable, else the
+// language folder that it's in (for example, fr/about.md or _posts/fr/),
+// else the site's default language.
+func (f *file) Lang() string {
+	cfg := f.site.Config()
+	if lang := f.fm.String("lang", ""); lang != "" || !cfg.IsMultilingual() {
+		return lang
+	}
+	if lang, _ := cfg.LanguageFolder(f.relPath); lang != "" {
+		return lang
+	}
+	return cfg.DefaultLanguage()
+}
+
+// languageRelPath returns the relative path without its language folder.
+// This is the path that the permalink variables use, so that translations
+// of a document have the same permalink within their language trees.
+func (f *file) languageRelPath() string {
+	_, rel := f.site.Config().LanguageFolder(f.relPath)
+	return rel
+}
+
+// localizeURL adds the language prefix (for example, /fr) to the URL of a
+// document that isn't in the site's default language.
+func (f *file) localizeURL(url string) string {
+	var (
+		cfg  = f.site.Config()
+		lang = f.Lang()
+	)
+	if !cfg.IsLanguage(lang) || lang == cfg.DefaultLanguage() || strings.HasPrefix(url, "/"+lang+"/") {
+		return url
+	}
+	return "/" + lang + url
+}
+
+// renderer returns the renderer for the page's language. A Site that
+// supports multiple languages supplies a renderer for each, so that the t
+// filter uses the page's language.
+func (p *page) renderer() renderers.Renderers {
+	if s, ok := p.site.(interface {
+		LanguageRendererManager(string) renderers.Renderers
+	}); ok {
+		return s.LanguageRendererManager(p.Lang())
+	}
+	return p.site.RendererManager()
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +30,8 @@ type Page interface {
 	// TODO Should posts have their own interface?
 	PostDate() time.Time
 	IsPost() bool
+	// Lang returns the page's language, or "" if it doesn't have one.
+	Lang() string
 
 	Categories() []string
 	Tags() []string
@@ -68,7 +70,7 @@ type page struct {
 	content      string
 	contentError error
 	contentOnce  sync.Once
-	excerpt      interface{} // []byte or string, depending on rendering stage
+	excerpt      *Excerpt // nil if the page doesn't have an excerpt
 	rendered     bool
 }
 
@@ -85,6 +87,7 @@ func makePage(filename string, f file) (*page, error) {
 		firstLine: lineNo,
 		raw:       raw,
 	}
+	p.excerpt = newExcerpt(&p)
 	if err := p.setPermalink(); err != nil {
 		return nil, err
 	}
@@ -102,6 +105,7 @@ func (p *page) Reload() error {
 	}
 	p.firstLine = lineNo
 	p.raw = raw
+	p.excerpt = newExcerpt(p)
 	p.reset()
 	return nil
 }
@@ -129,9 +133,46 @@ func (p *page) FrontMatter() frontmatter.FrontMatter {
 	return p.fm
 }
 
-// Categories is in the Page interface
+// Categories is in the Page interface.
+// A post's categories are those in its front matter, followed by the
+// directories above its _posts or _drafts directory, in path order.
 func (p *page) Categories() []string {
-	return p.fm.SortedStringArray("categories")
+	categories := p.fm.SortedStringArray("categories")
+	if !p.IsPost() {
+		return categories
+	}
+	rel, err := filepath.Rel(p.site.Config().SourceDir(), utils.MustAbs(p.filename))
+	if err != nil {
+		return categories
+	}
+	dirs, _ := PostDirectoryCategories(filepath.ToSlash(rel))
+	if len(dirs) == 0 {
+		return categories
+	}
+	set := utils.MakeStringSet(categories)
+	for _, c := range dirs {
+		if !set[c] {
+			categories = append(categories, c)
+			set[c] = true
+		}
+	}
+	return categories
+}
+
+// PostDirectoryCategories returns the directories above the _posts or _drafts
+// directory in a slash-separated site-relative path, and a bool indicating
+// whether the path is inside such a directory.
+//
+// Jekyll uses these directories as post categories. For example,
+// engineering/infra/_posts/2020-01-01-x.md has categories engineering and infra.
+func PostDirectoryCategories(rel string) ([]string, bool) {
+	segments := strings.Split(path.Dir(rel), "/")
+	for i, s := range segments {
+		if s == "_posts" || s == "_drafts" {
+			return segments[:i], true
+		}
+	}
+	return nil, false
 }
 
 // IsPost is in the Page interface
@@ -146,19 +187,26 @@ func (p *page) Tags() []string {
 
 // TemplateContext returns the local variables for template evaluation
 func (p *page) TemplateContext() map[string]interface{} {
-	env := os.Getenv("JEKYLL_ENV")
-	if env == "" {
-		env = "development"
-	}
 	return map[string]interface{}{
 		"page": p,
-		"site": p.site,
+		"site": p.siteVariable(),
 		"jekyll": map[string]string{
-			"environment": env,
+			"environment": p.site.Config().Env(),
 			"version":     fmt.Sprintf("%s (gojekyll)", version.Version)},
 	}
 }
 
+// siteVariable returns the value of the site template variable. A Site can
+// customize this for each page; for example, to set site.related_posts.
+func (p *page) siteVariable() interface{} {
+	if s, ok := p.site.(interface {
+		PageSiteDrop(Page) interface{}
+	}); ok {
+		return s.PageSiteDrop(p)
+	}
+	return p.site
+}
+
 // PostDate is part of the Page interface.
 // FIXME move this back to Page interface, or re-work this entirely.
 func (f *file) PostDate() time.Time {
@@ -184,7 +232,7 @@ func (p *page) Write(w io.Writer) error {
 	cn := p.content
 	lo, ok := p.fm["layout"].(string)
 	if ok && lo != "" {
-		rm := p.site.RendererManager()
+		rm := p.renderer()
 		b, err := rm.ApplyLayout(lo, []byte(cn), p.TemplateContext())
 		if err != nil {
 			return err
@@ -199,12 +247,14 @@ func (p *page) Write(w io.Writer) error {
 // Content computes the page content.
 func (p *page) Render() error {
 	p.contentOnce.Do(func() {
-		cn, ex, err := p.computeContent()
+		cn, err := p.computeContent()
+		if err == nil && p.excerpt != nil {
+			_, err = p.excerpt.Content()
+		}
 		p.Lock()
 		defer p.Unlock()
 		p.content = cn
 		p.contentError = utils.WrapPathError(err, p.filename)
-		p.excerpt = ex
 		p.rendered = true
 	})
 	return p.contentError
@@ -217,39 +267,21 @@ func (p *page) SetContent(content string) {
 	p.contentError = nil
 }
 
-func (p *page) computeContent() (cn string, ex string, err error) {
-	pl := p.site.RendererManager()
+func (p *page) computeContent() (string, error) {
+	pl := p.renderer()
 	buf := new(bytes.Buffer)
-	err = pl.Render(buf, p.raw, p.TemplateContext(), p.filename, p.firstLine)
-	if err != nil {
-		return
-	}
-	cn = buf.String()
-	ex = cn
-	pos := strings.Index(ex, p.site.Config().ExcerptSeparator)
-	if pos >= 0 {
-		ex = ex[:pos]
-	}
-	return
+	err := pl.Render(buf, p.raw, p.TemplateContext(), p.filename, p.firstLine)
+	return buf.String(), err
 }
 
+// Excerpt returns the value of page.excerpt: the excerpt front matter
+// variable if it's set, else an *Excerpt, else nil.
 func (p *page) Excerpt() interface{} {
 	if exc, ok := p.fm["excerpt"]; ok {
 		return exc
 	}
-	p.RLock()
-	defer p.RUnlock()
-	if p.rendered {
-		return p.excerpt
-	}
-	return p.extractExcerpt()
-}
-
-func (p *page) extractExcerpt() []byte {
-	raw := p.raw
-	pos := bytes.Index(raw, []byte(p.site.Config().ExcerptSeparator))
-	if pos >= 0 {
-		return raw[:pos]
+	if p.excerpt == nil {
+		return nil
 	}
-	return raw
+	return p.excerpt
 }
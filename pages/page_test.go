@@ -27,6 +27,13 @@ func TestPage_TemplateContext(t *testing.T) {
 	tc = p.TemplateContext()
 	j = tc["jekyll"].(map[string]string)
 	require.Equal(t, "production", j["environment"])
+
+	s.cfg.Environment = "staging"
+	p = page{file: file{site: s}}
+	tc = p.TemplateContext()
+	j = tc["jekyll"].(map[string]string)
+	require.Equal(t, "staging", j["environment"])
+	os.Setenv("JEKYLL_ENV", "") // nolint: errcheck
 }
 
 func TestPage_Categories(t *testing.T) {
@@ -35,6 +42,22 @@ func TestPage_Categories(t *testing.T) {
 	f := file{site: s, fm: fm}
 	p := page{file: f}
 	require.Equal(t, []string{"a", "b"}, p.Categories())
+
+	fm = frontmatter.FrontMatter{"categories": "b", "collection": "posts"}
+	f = file{site: s, fm: fm, filename: filepath.Join(s.cfg.Source, "c", "a", "_posts", "2017-01-01-post.md")}
+	p = page{file: f}
+	require.Equal(t, []string{"b", "c", "a"}, p.Categories())
+}
+
+func TestPostDirectoryCategories(t *testing.T) {
+	dirs, ok := PostDirectoryCategories("_posts/2017-01-01-post.md")
+	require.True(t, ok)
+	require.Empty(t, dirs)
+	dirs, ok = PostDirectoryCategories("a/b/_drafts/sub/post.md")
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "b"}, dirs)
+	_, ok = PostDirectoryCategories("a/b/post.md")
+	require.False(t, ok)
 }
 
 func TestPage_Write(t *testing.T) {
@@ -2,6 +2,7 @@ package pages
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -34,6 +35,34 @@ var permalinkDateVariables = map[string]string{
 	"second":     "05",
 	"year":       "2006",
 	"short_year": "06",
+	// Jekyll 4
+	"i_month":     "1",
+	"short_month": "Jan",
+	"long_month":  "January",
+	"short_day":   "Mon",
+	"long_day":    "Monday",
+}
+
+// permalinkDateFunctions maps Jekyll permalink template variable names
+// to functions, for variables that time.Format can't produce.
+var permalinkDateFunctions = map[string]func(time.Time) string{
+	"lower_month": func(t time.Time) string { return strings.ToLower(t.Format("January")) },
+	"y_day":       func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) },
+	"week": func(t time.Time) string {
+		_, week := t.ISOWeek()
+		return fmt.Sprintf("%02d", week)
+	},
+	"w_year": func(t time.Time) string {
+		year, _ := t.ISOWeek()
+		return strconv.Itoa(year)
+	},
+	"w_day": func(t time.Time) string {
+		// ISO 8601 weekday: Monday is 1, and Sunday is 7
+		if t.Weekday() == time.Sunday {
+			return "7"
+		}
+		return strconv.Itoa(int(t.Weekday()))
+	},
 }
 
 var templateVariableMatcher = regexp.MustCompile(`:\w+\b`)
@@ -41,7 +70,7 @@ var templateVariableMatcher = regexp.MustCompile(`:\w+\b`)
 // See https://jekyllrb.com/docs/permalinks/#template-variables
 func (p *page) permalinkVariables() map[string]string {
 	var (
-		relpath = p.relPath
+		relpath = p.languageRelPath()
 		root    = utils.TrimExt(relpath)
 		name    = filepath.Base(root)
 		slug    = p.fm.String("slug", utils.Slugify(name))
@@ -49,28 +78,67 @@ func (p *page) permalinkVariables() map[string]string {
 		date = p.PostDate().In(time.Local)
 	)
 	vars := map[string]string{
-		"categories": strings.Join(p.Categories(), "/"),
-		"collection": p.fm.String("collection", ""),
-		"name":       utils.Slugify(name),
-		"path":       "/" + root, // TODO are we removing and then adding this?
-		"slug":       slug,
-		"title":      utils.Slugify(p.fm.String("title", name)),
-		"y_day":      strconv.Itoa(p.modTime.YearDay()),
+		"categories":           strings.Join(p.Categories(), "/"),
+		"collection":           p.fm.String("collection", ""),
+		"name":                 utils.Slugify(name),
+		"path":                 "/" + root, // TODO are we removing and then adding this?
+		"slug":                 slug,
+		"slugified_categories": slugifyCategories(p.Categories()),
+		"title":                utils.Slugify(p.fm.String("title", name)),
 		// Undocumented but evident:
 		"output_ext": p.OutputExt(),
 	}
 	for k, v := range permalinkDateVariables {
 		vars[k] = date.Format(v)
 	}
+	for k, fn := range permalinkDateFunctions {
+		vars[k] = fn(date)
+	}
 	return vars
 }
 
+func slugifyCategories(categories []string) string {
+	slugs := make([]string, 0, len(categories))
+	for _, c := range categories {
+		if s := strings.Trim(utils.Slugify(c), "-"); s != "" {
+			slugs = append(slugs, s)
+		}
+	}
+	return strings.Join(slugs, "/")
+}
+
 func (p *page) computePermalink(vars map[string]string) (src string, err error) {
-	pattern := p.fm.String("permalink", DefaultPermalinkPattern)
+	url, err := expandPermalinkPattern(p.fm.String("permalink", p.defaultPermalinkPattern()), vars)
+	if err != nil {
+		return "", err
+	}
+	return p.localizeURL(url), nil
+}
+
+// defaultPermalinkPattern returns the permalink pattern for a page that
+// doesn't specify one. (Collection documents get theirs from the collection.)
+//
+// As in Jekyll, if the site permalink style ends in a slash, as "pretty"
+// does, HTML pages are output at directory-style URLs: about.md is at
+// /about/, and dir/index.md is at /dir/.
+func (p *page) defaultPermalinkPattern() string {
+	style := p.site.Config().Permalink
+	if pat, found := PermalinkStyles[style]; found {
+		style = pat
+	}
+	if p.OutputExt() != ".html" || !strings.HasSuffix(style, "/") {
+		return DefaultPermalinkPattern
+	}
+	if path.Base(utils.TrimExt(p.languageRelPath())) == "index" {
+		return "/:path/../"
+	}
+	return "/:path/"
+}
+
+func expandPermalinkPattern(pattern string, templateVariables map[string]string) (string, error) {
 	if pat, found := PermalinkStyles[pattern]; found {
 		pattern = pat
 	}
-	templateVariables := p.permalinkVariables()
 	s, err := utils.SafeReplaceAllStringFunc(templateVariableMatcher, pattern, func(m string) (string, error) {
 		varname := m[1:]
 		value, found := templateVariables[varname]
@@ -85,6 +153,40 @@ func (p *page) computePermalink(vars map[string]string) (src string, err error)
 	return utils.URLPathClean("/" + s), nil
 }
 
+// staticCollectionPermalink computes the URL of a static file in a
+// collection, from the collection's permalink pattern. As in Jekyll, the
+// pattern's :path excludes the extension, which is appended after the
+// pattern is expanded; and the date and title variables are empty.
+func (f *file) staticCollectionPermalink() (string, error) {
+	var (
+		root = utils.TrimExt(f.relPath)
+		name = path.Base(root)
+		ext  = path.Ext(f.relPath)
+		vars = map[string]string{
+			"categories": "",
+			"collection": f.fm.String("collection", ""),
+			"name":       name,
+			"path":       "/" + root,
+			"slug":       utils.Slugify(name),
+			"title":      "",
+			"output_ext": "",
+
+			"slugified_categories": "",
+		}
+	)
+	for k := range permalinkDateVariables {
+		vars[k] = ""
+	}
+	for k := range permalinkDateFunctions {
+		vars[k] = ""
+	}
+	s, err := expandPermalinkPattern(f.fm.String("permalink", DefaultPermalinkPattern), vars)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(s, "/") + ext, nil
+}
+
 func (p *page) setPermalink() (err error) {
 	p.permalink, err = p.computePermalink(p.permalinkVariables())
 	return
@@ -25,8 +25,13 @@ var tests = []pathTest{
 
 	{"base", "date", "/a/b/2006/02/03/base.html"},
 	{"base", "pretty", "/a/b/2006/02/03/base/"},
-	{"base", "ordinal", "/a/b/2006/34/base.html"},
+	{"base", "ordinal", "/a/b/2006/034/base.html"},
 	{"base", "none", "/a/b/base.html"},
+
+	{"base", "/:slugified_categories/:name", "/a/b/base"},
+	{"base", "/:w_year/:week/:w_day/:name", "/2006/05/5/base"},
+	{"base", "/:short_month/:long_month/:lower_month/:name", "/Feb/February/february/base"},
+	{"base", "/:short_day/:long_day/:i_month/:name", "/Fri/Friday/2/base"},
 }
 
 var collectionTests = []pathTest{
@@ -79,3 +84,23 @@ func TestExpandPermalinkPattern(t *testing.T) {
 		require.Zero(t, p)
 	})
 }
+
+func TestDefaultPermalinkPattern(t *testing.T) {
+	cfg := config.Default()
+	cfg.Permalink = "pretty"
+	s := siteFake{t, cfg}
+	permalink := func(relpath, ext string) string {
+		f := file{site: s, relPath: relpath, fm: frontmatter.FrontMatter{}, outputExt: ext}
+		p := page{file: f}
+		url, err := p.computePermalink(p.permalinkVariables())
+		require.NoError(t, err)
+		return url
+	}
+	require.Equal(t, "/about/", permalink("about.md", ".html"))
+	require.Equal(t, "/", permalink("index.html", ".html"))
+	require.Equal(t, "/dir/", permalink("dir/index.md", ".html"))
+	require.Equal(t, "/style.css", permalink("style.css", ".css"))
+
+	s.cfg.Permalink = "date"
+	require.Equal(t, "/about.html", permalink("about.md", ".html"))
+}
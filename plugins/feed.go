@@ -56,7 +56,8 @@ func (p *jekyllFeedPlugin) feedMetaTag(ctx render.Context) (string, error) {
 	return tag, nil
 }
 
-// Taken verbatim from https://github.com/jekyll/jekyll-feed/
+// Taken from https://github.com/jekyll/jekyll-feed/, with the addition of
+// hreflang links to the translations of multilingual posts.
 const feedTemplateSource = `<?xml version="1.0" encoding="utf-8"?>
 {% if page.xsl %}
   <?xml-stylesheet type="text/xml" href="{{ '/feed.xslt.xml' | absolute_url }}"?>
@@ -94,7 +95,10 @@ const feedTemplateSource = `<?xml version="1.0" encoding="utf-8"?>
   {% for post in posts limit: 10 %}
     <entry{% if post.lang %}{{" "}}xml:lang="{{ post.lang }}"{% endif %}>
       <title type="html">{{ post.title | smartify | strip_html | normalize_whitespace | xml_escape }}</title>
-      <link href="{{ post.url | absolute_url }}" rel="alternate" type="text/html" title="{{ post.title | xml_escape }}" />
+      <link href="{{ post.url | absolute_url }}" rel="alternate" type="text/html" {% if post.lang %}hreflang="{{ post.lang }}" {% endif %}title="{{ post.title | xml_escape }}" />
+      {% for translation in post.translations %}
+        <link href="{{ translation.url | absolute_url }}" rel="alternate" type="text/html" hreflang="{{ translation.lang }}" title="{{ translation.title | xml_escape }}" />
+      {% endfor %}
       <published>{{ post.date | date_to_xmlschema }}</published>
       <updated>{{ post.last_modified_at | default: post.date | date_to_xmlschema }}</updated>
       <id>{{ post.id | absolute_url | xml_escape }}</id>
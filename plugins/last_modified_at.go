@@ -0,0 +1,112 @@
+package plugins
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/osteele/gojekyll/utils"
+)
+
+// lastModifiedAtPlugin emulates the jekyll-last-modified-at plugin. It
+// sets page.last_modified_at to the date of the last commit that changed the
+// page's source file, or to the file's modification time if git doesn't
+// know the file.
+//
+// One git log pass reads the dates of every file. The dates are cached until
+// the HEAD commit changes, so that rebuilds don't read them again.
+type lastModifiedAtPlugin struct {
+	plugin
+
+	sync.Mutex
+	dir   string               // source directory of the cached dates
+	head  string               // HEAD commit of the cached dates
+	dates map[string]time.Time // source-relative path -> commit date
+}
+
+func init() {
+	register("jekyll-last-modified-at", &lastModifiedAtPlugin{})
+}
+
+func (p *lastModifiedAtPlugin) PostReadSite(s Site) error {
+	dir := utils.MustAbs(s.Config().SourceDir())
+	dates := p.gitDates(dir)
+	for _, page := range s.Pages() {
+		fm := page.FrontMatter()
+		if _, ok := fm["last_modified_at"]; ok || page.Source() == "" {
+			continue
+		}
+		filename := utils.MustAbs(page.Source())
+		if rel, err := filepath.Rel(dir, filename); err == nil {
+			if t, found := dates[filepath.ToSlash(rel)]; found {
+				fm["last_modified_at"] = t
+				continue
+			}
+		}
+		if info, err := os.Stat(filename); err == nil {
+			fm["last_modified_at"] = info.ModTime()
+		}
+	}
+	return nil
+}
+
+// gitDates returns the commit dates of the files in dir, using the cached
+// dates if HEAD hasn't changed since they were read. It returns nil if dir
+// isn't in a git repository.
+func (p *lastModifiedAtPlugin) gitDates(dir string) map[string]time.Time {
+	head := getBuildRevision(dir)
+	if head == "" {
+		return nil
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.dir == dir && p.head == head {
+		return p.dates
+	}
+	// -z separates the filenames with NULs, and doesn't quote those that
+	// contain non-ASCII characters.
+	cmd := exec.Command("git", "log", "-z", "--format=%x01%ct", "--name-only", "--relative", "--", ".") // nolint: gas
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	p.dir, p.head, p.dates = dir, head, parseGitLogDates(out)
+	return p.dates
+}
+
+// parseGitLogDates parses the output of git log -z --format=%x01%ct
+// --name-only into a map from filename to the date of its most recent commit.
+//
+// The output is a sequence of NUL-terminated fields. A field that begins
+// with \x01 is a commit date; the following fields, up to the next date, are
+// the names of the files that the commit changed. The first name is preceded
+// by a newline.
+func parseGitLogDates(out []byte) map[string]time.Time {
+	var (
+		dates = map[string]time.Time{}
+		date  time.Time
+	)
+	for _, field := range bytes.Split(out, []byte{0}) {
+		field = bytes.TrimPrefix(field, []byte("\n"))
+		if bytes.HasPrefix(field, []byte{1}) {
+			secs, err := strconv.ParseInt(string(field[1:]), 10, 64)
+			if err != nil {
+				date = time.Time{}
+				continue
+			}
+			date = time.Unix(secs, 0)
+			continue
+		}
+		name := string(field)
+		if _, seen := dates[name]; name != "" && !date.IsZero() && !seen {
+			// git log lists the most recent commit first
+			dates[name] = date
+		}
+	}
+	return dates
+}
@@ -0,0 +1,50 @@
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitLogDates(t *testing.T) {
+	out := "\x011500000200\x00\nindex.md\x00_posts/2017-07-01-post.md\x00" +
+		"\x011500000100\x00\nindex.md\x00about.md\x00" +
+		"\x011500000000\x00\nline\nbreak.md\x00"
+	dates := parseGitLogDates([]byte(out))
+	require.Len(t, dates, 4)
+	require.Equal(t, time.Unix(1500000200, 0), dates["index.md"])
+	require.Equal(t, time.Unix(1500000200, 0), dates["_posts/2017-07-01-post.md"])
+	require.Equal(t, time.Unix(1500000100, 0), dates["about.md"])
+	require.Equal(t, time.Unix(1500000000, 0), dates["line\nbreak.md"])
+	require.Empty(t, parseGitLogDates(nil))
+}
+
+func TestLastModifiedAtPlugin_gitDates(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	dir, err := ioutil.TempDir("", "gojekyll-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	name := filepath.Join("_posts", "2020-01-01-café.md")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "_posts"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("text"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "add", "--date", "2020-01-02T00:00:00Z"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2020-01-02T00:00:00Z")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	dates := (&lastModifiedAtPlugin{}).gitDates(dir)
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC).Unix(), dates[filepath.ToSlash(name)].Unix())
+}
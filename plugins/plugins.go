@@ -43,6 +43,7 @@ type Site interface {
 type Page interface {
 	FrontMatter() frontmatter.FrontMatter
 	IsPost() bool
+	Source() string
 	URL() string
 }
 
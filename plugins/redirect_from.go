@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/osteele/gojekyll/pages"
@@ -40,13 +41,13 @@ func (p jekyllRedirectFromPlugin) PostReadSite(site Site) error {
 func (p jekyllRedirectFromPlugin) processRedirectFrom(site Site, ps []pages.Page) ([]pages.Document, error) {
 	var (
 		cfg          = site.Config()
-		siteurl      = cfg.AbsoluteURL
-		baseurl      = cfg.BaseURL
+		siteurl      = strings.TrimSuffix(cfg.AbsoluteURL, "/")
+		baseurl      = strings.TrimSuffix(cfg.BaseURL, "/")
 		prefix       = siteurl + baseurl
 		redirections = []pages.Document{}
 	)
 	addRedirectFrom := func(from string, to pages.Page) {
-		r := redirectionDoc{pages.PageEmbed{Path: from}, prefix + to.URL()}
+		r := redirectionDoc{pages.PageEmbed{Path: from}, prefix + to.URL(), baseurl + to.URL()}
 		redirections = append(redirections, &r)
 	}
 	for _, p := range ps {
@@ -68,8 +69,11 @@ func (p jekyllRedirectFromPlugin) processRedirectTo(site Site, ps []pages.Page)
 			return err
 		}
 		if len(sources) > 0 {
-			r := redirectionDoc{pages.PageEmbed{Path: p.URL()}, sources[0]}
+			r := redirectionDoc{pages.PageEmbed{Path: p.URL()}, sources[0], ""}
 			p.SetContent(r.Content())
+			// As in jekyll-redirect-from. The development server uses
+			// this to respond with a redirect.
+			p.FrontMatter()["redirect"] = map[string]interface{}{"from": p.URL(), "to": sources[0]}
 		}
 	}
 	return nil
@@ -96,7 +100,18 @@ func getStringArray(p pages.Page, fieldName string) (out []string, err error) {
 
 type redirectionDoc struct {
 	pages.PageEmbed
-	To string
+	To   string
+	path string // the target URL path, including the baseurl
+}
+
+// RedirectURL is in the pages.Redirect interface. It returns the target
+// path rather than To, so that the development server redirects to itself
+// instead of to the site url.
+func (d *redirectionDoc) RedirectURL() string {
+	if d.path != "" {
+		return d.path
+	}
+	return d.To
 }
 
 func (d *redirectionDoc) Content() string {
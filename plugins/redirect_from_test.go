@@ -0,0 +1,33 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/frontmatter"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/stretchr/testify/require"
+)
+
+type redirectTestPage struct {
+	pages.Page
+	url string
+	fm  frontmatter.FrontMatter
+}
+
+func (p redirectTestPage) URL() string                          { return p.url }
+func (p redirectTestPage) FrontMatter() frontmatter.FrontMatter { return p.fm }
+
+func TestRedirectFrom(t *testing.T) {
+	cfg := config.Default()
+	cfg.AbsoluteURL = "https://example.com/"
+	cfg.BaseURL = "/docs/"
+	ps := []pages.Page{redirectTestPage{url: "/about.html", fm: frontmatter.FrontMatter{"redirect_from": "/old.html"}}}
+	docs, err := jekyllRedirectFromPlugin{}.processRedirectFrom(siteFake{cfg, nil}, ps)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	r := docs[0].(*redirectionDoc)
+	require.Equal(t, "/old.html", r.URL())
+	require.Equal(t, "https://example.com/docs/about.html", r.To)
+	require.Equal(t, "/docs/about.html", r.RedirectURL())
+}
@@ -0,0 +1,270 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/osteele/gojekyll/pages"
+	"golang.org/x/net/html"
+)
+
+// searchPlugin writes a search index of the site's HTML pages, for
+// client-side search. The index is built from each page's output, after
+// rendering. It is configured by the search section of _config.yml:
+//
+//	search:
+//	  path: /search.json        # where to write the index
+//	  formats: [json, lunr]     # plain JSON records, and/or a prebuilt Lunr index
+//	  fields: [url, title, headings, content, tags]
+//	  exclude: [/404.html, /drafts/]
+//	  shard_size: 500           # split the index into files of this many pages
+//
+// Fields other than url, title, headings and content are front matter
+// variables. An exclude pattern that ends in a slash excludes the URLs that
+// begin with it; other patterns are matched as by path.Match. A page with
+// search: false in its front matter is also excluded.
+//
+// The Lunr index (at search.lunr.json) is in the format of Lunr 2's
+// lunr.Index.load. Its document references are the page URLs. Its terms
+// aren't stemmed, and its search pipeline trims but doesn't stem the query.
+//
+// If the index is split into shards, the index files are numbered
+// (search-1.json, search.lunr-1.json, ...), and the index path holds a
+// manifest that lists them by format.
+type searchPlugin struct{ plugin }
+
+func init() {
+	register("gojekyll-search", searchPlugin{})
+}
+
+var defaultSearchFields = []string{"url", "title", "headings", "content"}
+
+func (p searchPlugin) PostReadSite(s Site) error {
+	cfg := s.Config().Search
+	var (
+		indexPath = cfg.Path
+		formats   = cfg.Formats
+		fields    = cfg.Fields
+	)
+	if indexPath == "" {
+		indexPath = "/search.json"
+	}
+	indexPath = "/" + strings.TrimPrefix(indexPath, "/")
+	if len(formats) == 0 {
+		formats = []string{"json"}
+	}
+	if len(fields) == 0 {
+		fields = defaultSearchFields
+	}
+	for _, f := range formats {
+		if f != "json" && f != "lunr" {
+			return fmt.Errorf("search: unknown format %q", f)
+		}
+	}
+	ps := searchablePages(s)
+	shards := [][]pages.Page{ps}
+	if n := cfg.ShardSize; n > 0 && len(ps) > n {
+		shards = nil
+		for i := 0; i < len(ps); i += n {
+			end := i + n
+			if end > len(ps) {
+				end = len(ps)
+			}
+			shards = append(shards, ps[i:end])
+		}
+	}
+	manifest := map[string][]string{}
+	for _, format := range formats {
+		for i, shard := range shards {
+			shardPath := searchIndexPath(indexPath, format, i+1, len(shards))
+			d := &searchIndexDoc{pages.PageEmbed{Path: shardPath}, s, shard, fields, format}
+			s.AddDocument(d, true)
+			manifest[format] = append(manifest[format], s.Config().BaseURL+shardPath)
+		}
+	}
+	if len(shards) > 1 {
+		b, err := json.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		s.AddDocument(&staticContentDoc{pages.PageEmbed{Path: indexPath}, b}, true)
+	}
+	return nil
+}
+
+// searchIndexPath returns the path of an index file. For example, shard 2
+// of the Lunr index at /search.json is at /search.lunr-2.json.
+func searchIndexPath(indexPath, format string, shard, shards int) string {
+	ext := path.Ext(indexPath)
+	root := strings.TrimSuffix(indexPath, ext)
+	if format == "lunr" {
+		root += ".lunr"
+	}
+	if shards > 1 {
+		root += fmt.Sprintf("-%d", shard)
+	}
+	return root + ext
+}
+
+// searchablePages returns the pages that are output as HTML, and that
+// aren't excluded from the search index.
+func searchablePages(s Site) (out []pages.Page) {
+	cfg := s.Config()
+	for _, p := range s.Pages() {
+		fm := p.FrontMatter()
+		name := fm.String("collection", "")
+		switch {
+		case !p.Published() && !cfg.Unpublished:
+			continue
+		case p.OutputExt() != ".html" || !fm.Bool("search", true):
+			continue
+		case name != "" && name != "posts" && cfg.Collections[name]["output"] != true:
+			continue
+		case isExcludedFromSearch(p.URL(), cfg.Search.Exclude):
+			continue
+		}
+		out = append(out, p)
+	}
+	return
+}
+
+func isExcludedFromSearch(url string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(url, pattern) {
+			return true
+		}
+		if match, err := path.Match(pattern, url); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// A searchIndexDoc is an index file. It renders its pages when it's written.
+type searchIndexDoc struct {
+	pages.PageEmbed
+	site   Site
+	pages  []pages.Page
+	fields []string
+	format string
+}
+
+func (d *searchIndexDoc) Write(w io.Writer) error {
+	records := make([]map[string]interface{}, 0, len(d.pages))
+	refs := make([]string, 0, len(d.pages))
+	for _, p := range d.pages {
+		buf := new(bytes.Buffer)
+		if err := p.Write(buf); err != nil {
+			return err
+		}
+		records = append(records, d.record(p, parseSearchText(buf.Bytes())))
+		refs = append(refs, d.site.Config().BaseURL+p.URL())
+	}
+	var index interface{} = records
+	if d.format == "lunr" {
+		index = newLunrIndex(records, refs, d.fields)
+	}
+	return json.NewEncoder(w).Encode(index)
+}
+
+// record returns the configured fields of a page's index record.
+func (d *searchIndexDoc) record(p pages.Page, text searchText) map[string]interface{} {
+	fm := p.FrontMatter()
+	title := fm.String("title", text.title)
+	r := map[string]interface{}{}
+	for _, f := range d.fields {
+		switch f {
+		case "url":
+			r[f] = d.site.Config().BaseURL + p.URL()
+		case "title":
+			r[f] = title
+		case "headings":
+			r[f] = text.headings
+		case "content":
+			r[f] = text.content
+		default:
+			if v, ok := fm[f]; ok {
+				r[f] = v
+			}
+		}
+	}
+	return r
+}
+
+// searchText is the text of an HTML document.
+type searchText struct {
+	title    string
+	headings []string
+	content  string
+}
+
+// Elements whose text isn't indexed: non-content, and site navigation.
+var searchSkippedElements = map[string]bool{
+	"footer": true, "head": true, "header": true, "nav": true,
+	"noscript": true, "script": true, "style": true, "template": true,
+}
+
+// parseSearchText returns the title, headings, and text of an HTML document.
+func parseSearchText(doc []byte) searchText {
+	var (
+		z        = html.NewTokenizer(bytes.NewReader(doc))
+		text     searchText
+		content  []string
+		heading  []string
+		skip     = 0 // depth of skipped elements
+		inTitle  = false
+		inHeader = false
+	)
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			text.content = strings.Join(content, " ")
+			return text
+		case html.StartTagToken, html.EndTagToken:
+			tn, _ := z.TagName()
+			name, start := string(tn), tt == html.StartTagToken
+			switch {
+			case name == "title":
+				inTitle = start
+			case searchSkippedElements[name] && start:
+				skip++
+			case searchSkippedElements[name] && skip > 0:
+				skip--
+			case len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6':
+				if !start && inHeader && skip == 0 {
+					text.headings = append(text.headings, strings.Join(heading, " "))
+				}
+				inHeader, heading = start, nil
+			}
+		case html.TextToken:
+			words := strings.Join(strings.Fields(string(z.Text())), " ")
+			switch {
+			case words == "":
+			case inTitle:
+				text.title = words
+			case skip > 0:
+			default:
+				content = append(content, words)
+				if inHeader {
+					heading = append(heading, words)
+				}
+			}
+		}
+	}
+}
+
+// A staticContentDoc is a document with fixed content.
+type staticContentDoc struct {
+	pages.PageEmbed
+	content []byte
+}
+
+func (d *staticContentDoc) Write(w io.Writer) error {
+	_, err := w.Write(d.content)
+	return err
+}
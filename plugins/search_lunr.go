@@ -0,0 +1,153 @@
+package plugins
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The BM25 parameters that Lunr uses by default.
+const (
+	lunrK1 = 1.2
+	lunrB  = 0.75
+)
+
+// lunrIndex is a Lunr 2 index, in the serialization format that
+// lunr.Index.load reads.
+type lunrIndex struct {
+	Version       string          `json:"version"`
+	Fields        []string        `json:"fields"`
+	FieldVectors  [][]interface{} `json:"fieldVectors"`  // [fieldName/ref, [termIndex, score, ...]]
+	InvertedIndex [][]interface{} `json:"invertedIndex"` // [term, posting]
+	Pipeline      []string        `json:"pipeline"`
+}
+
+// newLunrIndex builds a Lunr index of search records. refs are the records'
+// document references, their URLs. The fields other than url are indexed.
+//
+// This follows lunr.Builder, without the stop word filter and stemmer.
+func newLunrIndex(records []map[string]interface{}, refs []string, fields []string) *lunrIndex {
+	var indexed []string
+	for _, f := range fields {
+		if f != "url" {
+			indexed = append(indexed, f)
+		}
+	}
+	type fieldRef struct{ field, ref string }
+	var (
+		termIndex       = map[string]int{}
+		postings        = map[string]map[string]map[string]interface{}{} // term -> field -> ref -> metadata
+		termFreqs       = map[fieldRef]map[string]int{}
+		fieldLengths    = map[fieldRef]int{}
+		totalLengths    = map[string]int{}
+		fieldRefs       []fieldRef
+		documentCount   = len(records)
+		averageLength   = map[string]float64{}
+		serializedIndex = &lunrIndex{
+			Version:  "2.3.9",
+			Fields:   indexed,
+			Pipeline: []string{"trimmer"},
+		}
+	)
+	for i, r := range records {
+		ref := refs[i]
+		for _, field := range indexed {
+			fr := fieldRef{field, ref}
+			fieldRefs = append(fieldRefs, fr)
+			terms := lunrTokens(r[field])
+			freqs := map[string]int{}
+			for _, term := range terms {
+				freqs[term]++
+				if _, ok := termIndex[term]; !ok {
+					termIndex[term] = len(termIndex)
+					postings[term] = map[string]map[string]interface{}{}
+					for _, f := range indexed {
+						postings[term][f] = map[string]interface{}{}
+					}
+				}
+				postings[term][field][ref] = struct{}{}
+			}
+			termFreqs[fr] = freqs
+			fieldLengths[fr] = len(terms)
+			totalLengths[field] += len(terms)
+		}
+	}
+	for _, field := range indexed {
+		if documentCount > 0 {
+			averageLength[field] = float64(totalLengths[field]) / float64(documentCount)
+		}
+	}
+	idf := func(term string) float64 {
+		docs := 0
+		for _, refs := range postings[term] {
+			docs += len(refs)
+		}
+		x := (float64(documentCount) - float64(docs) + 0.5) / (float64(docs) + 0.5)
+		return math.Log(1 + math.Abs(x))
+	}
+	for _, fr := range fieldRefs {
+		var (
+			freqs  = termFreqs[fr]
+			terms  = make([]string, 0, len(freqs))
+			vector = []interface{}{}
+		)
+		for term := range freqs {
+			terms = append(terms, term)
+		}
+		sort.Slice(terms, func(i, j int) bool { return termIndex[terms[i]] < termIndex[terms[j]] })
+		for _, term := range terms {
+			tf := float64(freqs[term])
+			norm := 1 - lunrB + lunrB*float64(fieldLengths[fr])/averageLength[fr.field]
+			score := idf(term) * (lunrK1 + 1) * tf / (lunrK1*norm + tf)
+			vector = append(vector, termIndex[term], math.Floor(score*1000+0.5)/1000)
+		}
+		serializedIndex.FieldVectors = append(serializedIndex.FieldVectors,
+			[]interface{}{fr.field + "/" + fr.ref, vector})
+	}
+	terms := make([]string, 0, len(termIndex))
+	for term := range termIndex {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	for _, term := range terms {
+		posting := map[string]interface{}{"_index": termIndex[term]}
+		for field, refs := range postings[term] {
+			posting[field] = refs
+		}
+		serializedIndex.InvertedIndex = append(serializedIndex.InvertedIndex, []interface{}{term, posting})
+	}
+	return serializedIndex
+}
+
+// lunrTokens splits a field value into terms, as Lunr's tokenizer and
+// trimmer do: lowercased, split at whitespace and hyphens, and trimmed of
+// leading and trailing non-word characters.
+func lunrTokens(value interface{}) (tokens []string) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		text = v
+	case []string:
+		text = strings.Join(v, " ")
+	case []interface{}:
+		words := make([]string, len(v))
+		for i, w := range v {
+			words[i] = fmt.Sprint(w)
+		}
+		text = strings.Join(words, " ")
+	default:
+		text = fmt.Sprint(v)
+	}
+	isSeparator := func(r rune) bool { return unicode.IsSpace(r) || r == '-' }
+	isNonWord := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' }
+	for _, token := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+		if token = strings.TrimFunc(token, isNonWord); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return
+}
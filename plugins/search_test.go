@@ -0,0 +1,65 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchText(t *testing.T) {
+	doc := `<html><head><title>Page  Title</title><style>p {}</style></head>
+<body><nav><h2>Menu</h2></nav>
+<h1>First <em>heading</em></h1><p>Some
+text.</p><script>var x;</script><h2>Second</h2><p>More text.</p>
+<footer>Copyright</footer></body></html>`
+	text := parseSearchText([]byte(doc))
+	require.Equal(t, "Page Title", text.title)
+	require.Equal(t, []string{"First heading", "Second"}, text.headings)
+	require.Equal(t, "First heading Some text. Second More text.", text.content)
+}
+
+func TestSearchIndexPath(t *testing.T) {
+	require.Equal(t, "/search.json", searchIndexPath("/search.json", "json", 1, 1))
+	require.Equal(t, "/search.lunr.json", searchIndexPath("/search.json", "lunr", 1, 1))
+	require.Equal(t, "/search-2.json", searchIndexPath("/search.json", "json", 2, 3))
+	require.Equal(t, "/search.lunr-2.json", searchIndexPath("/search.json", "lunr", 2, 3))
+}
+
+func TestIsExcludedFromSearch(t *testing.T) {
+	patterns := []string{"/404.html", "/drafts/", "/*/private.html"}
+	require.True(t, isExcludedFromSearch("/404.html", patterns))
+	require.True(t, isExcludedFromSearch("/drafts/a/b.html", patterns))
+	require.True(t, isExcludedFromSearch("/docs/private.html", patterns))
+	require.False(t, isExcludedFromSearch("/docs/public.html", patterns))
+}
+
+func TestNewLunrIndex(t *testing.T) {
+	records := []map[string]interface{}{
+		{"url": "/a.html", "title": "Hello, world"},
+		{"url": "/b.html", "title": "hello"},
+	}
+	refs := []string{"/a.html", "/b.html"}
+	index := newLunrIndex(records, refs, []string{"url", "title"})
+	require.Equal(t, []string{"title"}, index.Fields)
+	require.Equal(t, [][]interface{}{
+		{"title//a.html", []interface{}{0, 0.16, 1, 0.61}},
+		{"title//b.html", []interface{}{0, 0.211}},
+	}, index.FieldVectors)
+	require.Len(t, index.InvertedIndex, 2)
+	require.Equal(t, "hello", index.InvertedIndex[0][0])
+	posting := index.InvertedIndex[0][1].(map[string]interface{})
+	require.Equal(t, 0, posting["_index"])
+	require.Len(t, posting["title"], 2)
+	require.Equal(t, "world", index.InvertedIndex[1][0])
+
+	// the references are the page URLs, even if url isn't a search field
+	records = []map[string]interface{}{{"title": "hello"}}
+	index = newLunrIndex(records, refs[:1], []string{"title"})
+	require.Equal(t, "title//a.html", index.FieldVectors[0][0])
+}
+
+func TestLunrTokens(t *testing.T) {
+	require.Equal(t, []string{"hello", "world", "full", "text", "café"}, lunrTokens("Hello, world! full-text (café)"))
+	require.Equal(t, []string{"go", "web"}, lunrTokens([]interface{}{"Go", "web"}))
+	require.Nil(t, lunrTokens(nil))
+}
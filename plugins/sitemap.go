@@ -12,18 +12,25 @@ func (p *sitemapPlugin) PostReadSite(s Site) error {
 	return nil
 }
 
-// Taken verbatim from https://github.com/jekyll/jekyll-sitemap-plugin/
+// Taken from https://github.com/jekyll/jekyll-sitemap-plugin/, with the
+// addition of hreflang alternates for the translations of multilingual pages.
 const sitemapTemplateSource = `<?xml version="1.0" encoding="UTF-8"?>
 {% if page.xsl %}
   <?xml-stylesheet type="text/xsl" href="{{ "/sitemap.xsl" | absolute_url }}"?>
 {% endif %}
-<urlset xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.sitemaps.org/schemas/sitemap/0.9 http://www.sitemaps.org/schemas/sitemap/0.9/sitemap.xsd" xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<urlset xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.sitemaps.org/schemas/sitemap/0.9 http://www.sitemaps.org/schemas/sitemap/0.9/sitemap.xsd" xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">
   {% assign collections = site.collections | where_exp:'collection','collection.output != false' %}
   {% for collection in collections %}
     {% assign docs = collection.docs | where_exp:'doc','doc.sitemap != false' %}
     {% for doc in docs %}
       <url>
         <loc>{{ doc.url | replace:'/index.html','/' | absolute_url | xml_escape }}</loc>
+        {% if doc.translations %}
+          <xhtml:link rel="alternate" hreflang="{{ doc.lang }}" href="{{ doc.url | replace:'/index.html','/' | absolute_url | xml_escape }}" />
+          {% for translation in doc.translations %}
+            <xhtml:link rel="alternate" hreflang="{{ translation.lang }}" href="{{ translation.url | replace:'/index.html','/' | absolute_url | xml_escape }}" />
+          {% endfor %}
+        {% endif %}
         {% if doc.last_modified_at or doc.date %}
           <lastmod>{{ doc.last_modified_at | default: doc.date | date_to_xmlschema }}</lastmod>
         {% endif %}
@@ -35,6 +42,12 @@ const sitemapTemplateSource = `<?xml version="1.0" encoding="UTF-8"?>
   {% for page in pages %}
     <url>
       <loc>{{ page.url | replace:'/index.html','/' | absolute_url | xml_escape }}</loc>
+      {% if page.translations %}
+        <xhtml:link rel="alternate" hreflang="{{ page.lang }}" href="{{ page.url | replace:'/index.html','/' | absolute_url | xml_escape }}" />
+        {% for translation in page.translations %}
+          <xhtml:link rel="alternate" hreflang="{{ translation.lang }}" href="{{ translation.url | replace:'/index.html','/' | absolute_url | xml_escape }}" />
+        {% endfor %}
+      {% endif %}
       {% if page.last_modified_at %}
         <lastmod>{{ page.last_modified_at | date_to_xmlschema }}</lastmod>
       {% endif %}
@@ -31,6 +31,7 @@ type Manager struct {
 type Options struct {
 	RelativeFilenameToURL tags.LinkTagHandler
 	ThemeDir              string
+	Translations          []interface{} // translation tables for the t filter
 }
 
 // New makes a rendering manager.
@@ -43,6 +44,16 @@ func New(c config.Config, options Options) (*Manager, error) {
 	return &p, nil
 }
 
+// WithTranslations returns a manager that shares this manager's settings and
+// SASS files, but whose Liquid engine's t filter uses the translation tables.
+// The caller is responsible for configuring the new engine, as for a new manager.
+func (p *Manager) WithTranslations(tables ...interface{}) *Manager {
+	m := *p
+	m.Translations = tables
+	m.liquidEngine = m.makeLiquidEngine()
+	return &m
+}
+
 // sourceDir returns the site source directory. Seeing how far we can bend
 // the Law of Demeter.
 func (p *Manager) sourceDir() string {
@@ -93,6 +104,7 @@ func (p *Manager) makeLiquidEngine() *liquid.Engine {
 	}
 	engine := liquid.NewEngine()
 	filters.AddJekyllFilters(engine, &p.cfg)
+	filters.AddTranslationFilter(engine, p.Translations...)
 	tags.AddJekyllTags(engine, &p.cfg, dirs, p.RelativeFilenameToURL)
 	return engine
 }
@@ -0,0 +1,580 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osteele/gojekyll/pages"
+	"github.com/osteele/gojekyll/site"
+	"github.com/osteele/gojekyll/utils"
+	"github.com/osteele/liquid"
+)
+
+// The dashboard is a set of development pages, outside the site, that show
+// its routes, problems, variables, and render timings. Each view is also
+// available as JSON, at the view's path with a .json suffix.
+const dashboardPath = "/__gojekyll/"
+
+// A buildLog records the problems and render timings since the site was
+// loaded, for the dashboard. After each load, checkSite renders every page
+// and runs the doctor checks; requests then update the pages' entries.
+type buildLog struct {
+	sync.Mutex
+	generation   int                      // incremented each time the site is loaded
+	checked      bool                     // whether checkSite has finished for this generation
+	loadError    error                    // the error from the last reload, if it failed
+	renderErrors map[string]error         // keyed by URL
+	timings      map[string]time.Duration // keyed by URL
+	warnings     []string                 // from site.Diagnose
+}
+
+// reset is called when the server swaps in a new site. It returns the new
+// generation.
+func (l *buildLog) reset() int {
+	l.Lock()
+	defer l.Unlock()
+	l.generation++
+	l.checked = false
+	l.loadError = nil
+	l.renderErrors = nil
+	l.timings = nil
+	l.warnings = nil
+	return l.generation
+}
+
+func (l *buildLog) failedLoad(err error) {
+	l.Lock()
+	defer l.Unlock()
+	l.loadError = err
+}
+
+func (l *buildLog) currentGeneration() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.generation
+}
+
+func (l *buildLog) rendered(url string, elapsed time.Duration, err error) {
+	l.Lock()
+	defer l.Unlock()
+	l.record(url, elapsed, err)
+}
+
+// record is rendered, without the lock.
+func (l *buildLog) record(url string, elapsed time.Duration, err error) {
+	if l.renderErrors == nil {
+		l.renderErrors = map[string]error{}
+		l.timings = map[string]time.Duration{}
+	}
+	l.timings[url] = elapsed
+	if err != nil {
+		l.renderErrors[url] = err
+	} else {
+		delete(l.renderErrors, url)
+	}
+}
+
+// finishCheck records the results of checkSite, unless the site has been
+// reloaded since the check began. A page that a request has rendered in the
+// meantime keeps the request's result.
+func (l *buildLog) finishCheck(generation int, errs map[string]error, timings map[string]time.Duration, warnings []string) {
+	l.Lock()
+	defer l.Unlock()
+	if generation != l.generation {
+		return
+	}
+	for u, elapsed := range timings {
+		if _, found := l.timings[u]; !found {
+			l.record(u, elapsed, errs[u])
+		}
+	}
+	l.checked = true
+	l.warnings = warnings
+}
+
+// checkSite renders each of the site's pages, and runs the doctor checks, so
+// that the dashboard can report the problems in pages that haven't been
+// requested. The server calls this, in its own goroutine, each time it loads
+// the site. It holds the server's read lock only while it renders each page,
+// and it stops if the server loads the site again.
+func (s *Server) checkSite(site *site.Site, generation int) {
+	var (
+		errs    = map[string]error{}
+		timings = map[string]time.Duration{}
+		urls    = map[string]string{} // absolute source path -> URL
+	)
+	s.RLock()
+	docs := make([]pages.Document, 0, len(site.Routes))
+	for _, d := range site.Routes {
+		docs = append(docs, d)
+		if d.Source() != "" {
+			urls[utils.MustAbs(d.Source())] = d.URL()
+		}
+	}
+	s.RUnlock()
+	for _, d := range docs {
+		// A reload replaces or modifies the site; its own check takes over.
+		if s.log.currentGeneration() != generation {
+			return
+		}
+		u := d.URL()
+		if _, seen := timings[u]; seen || d.IsStatic() {
+			continue
+		}
+		if _, ok := redirectURL(d); ok {
+			continue
+		}
+		start := time.Now()
+		s.RLock()
+		err := site.WriteDocument(ioutil.Discard, d)
+		s.RUnlock()
+		if err != nil {
+			// Writing any page renders them all, so the error can be in
+			// another page's source.
+			if se, ok := err.(liquid.SourceError); ok && urls[utils.MustAbs(se.Path())] != "" {
+				u = urls[utils.MustAbs(se.Path())]
+			}
+			errs[u] = err
+		}
+		timings[d.URL()] = time.Since(start)
+	}
+	s.RLock()
+	warnings, err := site.Diagnose()
+	s.RUnlock()
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	s.log.finishCheck(generation, errs, timings, warnings)
+}
+
+// dashboardHandler returns the handler for a dashboard request. Like route,
+// it's called while the server holds the read lock.
+func (s *Server) dashboardHandler(r *http.Request) http.HandlerFunc {
+	view := strings.Trim(strings.TrimPrefix(r.URL.Path, dashboardPath), "/")
+	asJSON := strings.HasSuffix(view, ".json")
+	view = strings.TrimSuffix(view, ".json")
+	var (
+		data interface{}
+		err  error
+	)
+	switch view {
+	case "", "index":
+		view, data = "index", s.dashboardIndex()
+	case "routes":
+		data = s.dashboardRoutes()
+	case "problems":
+		data = s.dashboardProblems()
+	case "variables":
+		data, err = s.dashboardVariables(r.URL.Query())
+	case "timings":
+		data = s.dashboardTimings()
+	default:
+		return http.NotFound
+	}
+	if err != nil {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			http.Error(rw, err.Error(), http.StatusNotFound)
+		}
+	}
+	if asJSON {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+			enc := json.NewEncoder(rw)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(data); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+			}
+		}
+	}
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := dashboardTemplates[view].Execute(rw, dashboardPage{
+			Title: dashboardTitles[view],
+			JSON:  dashboardPath + view + ".json?" + r.URL.RawQuery,
+			Data:  data,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+		}
+	}
+}
+
+type dashboardPage struct {
+	Title, JSON string
+	Data        interface{}
+}
+
+type dashboardIndex struct {
+	SiteURL  string `json:"site_url"`
+	Routes   int    `json:"routes"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+}
+
+func (s *Server) dashboardIndex() dashboardIndex {
+	problems := s.dashboardProblems()
+	return dashboardIndex{
+		SiteURL:  s.siteHref("/"),
+		Routes:   len(s.Site.Routes),
+		Errors:   len(problems.Errors),
+		Warnings: len(problems.Warnings),
+	}
+}
+
+// siteHref returns the server path of a site URL.
+func (s *Server) siteHref(u string) string {
+	return strings.TrimSuffix(s.Site.Config().BaseURL, "/") + u
+}
+
+type dashboardRoute struct {
+	URL    string `json:"url"`
+	Href   string `json:"href"`             // the URL, including the baseurl
+	Source string `json:"source,omitempty"` // relative to the source directory; empty for generated documents
+	Static bool   `json:"static"`
+}
+
+func (s *Server) dashboardRoutes() []dashboardRoute {
+	var (
+		site   = s.Site
+		routes = make([]dashboardRoute, 0, len(site.Routes))
+	)
+	for u, d := range site.Routes {
+		r := dashboardRoute{URL: u, Href: s.siteHref(u), Static: d.IsStatic()}
+		if d.Source() != "" {
+			r.Source = site.RelativePath(d.Source())
+		}
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].URL < routes[j].URL })
+	return routes
+}
+
+type dashboardProblems struct {
+	Checking bool             `json:"checking"` // true until the site's pages have been checked
+	Errors   []dashboardError `json:"errors"`
+	Warnings []string         `json:"warnings"`
+}
+
+type dashboardError struct {
+	URL     string        `json:"url,omitempty"` // empty for an error reading the site
+	Href    string        `json:"href,omitempty"`
+	Message string        `json:"message"`
+	Path    string        `json:"path,omitempty"` // the file that contains the error
+	Line    int           `json:"line,omitempty"`
+	Excerpt template.HTML `json:"excerpt,omitempty"` // source lines around the error
+}
+
+func (s *Server) newDashboardError(url string, err error) dashboardError {
+	excerpt, path := fileErrorContext(err)
+	e := dashboardError{URL: url, Message: err.Error(), Path: path, Excerpt: template.HTML(excerpt)} // nolint: gas
+	if url != "" {
+		e.Href = s.siteHref(url)
+	}
+	if se, ok := err.(liquid.SourceError); ok {
+		e.Line = se.LineNumber()
+	}
+	return e
+}
+
+// dashboardProblems returns the errors from reading the site and from
+// rendering its pages, and the problems that the doctor command reports.
+// These are from the last check of the site, updated by the requests since.
+func (s *Server) dashboardProblems() dashboardProblems {
+	s.log.Lock()
+	defer s.log.Unlock()
+	problems := dashboardProblems{Checking: !s.log.checked, Warnings: s.log.warnings}
+	if err := s.log.loadError; err != nil {
+		problems.Errors = append(problems.Errors, s.newDashboardError("", err))
+	}
+	for u, err := range s.log.renderErrors {
+		problems.Errors = append(problems.Errors, s.newDashboardError(u, err))
+	}
+	sort.Slice(problems.Errors, func(i, j int) bool { return problems.Errors[i].URL < problems.Errors[j].URL })
+	return problems
+}
+
+type dashboardVariables struct {
+	Page    string              `json:"page,omitempty"` // a URL or filename; empty for the site
+	Key     string              `json:"key,omitempty"`  // a dotted property path, e.g. data.authors.0
+	Value   interface{}         `json:"value"`
+	Parents []dashboardLink     `json:"-"`
+	Entries []dashboardVariable `json:"-"`
+}
+
+type dashboardLink struct{ Name, Href string }
+
+// A dashboardVariable is a row in the HTML view of a map or list.
+type dashboardVariable struct {
+	Name, Href, Summary string
+}
+
+// dashboardVariables returns the variables of the site or a page, at the
+// optional dotted key. The JSON value is truncated at a depth of three.
+func (s *Server) dashboardVariables(q url.Values) (*dashboardVariables, error) {
+	var (
+		site = s.Site
+		v    = &dashboardVariables{Page: q.Get("page"), Key: q.Get("key")}
+		data interface{}
+	)
+	href := func(key string) string {
+		q := url.Values{}
+		if v.Page != "" {
+			q.Set("page", v.Page)
+		}
+		if key != "" {
+			q.Set("key", key)
+		}
+		return dashboardPath + "variables?" + q.Encode()
+	}
+	switch {
+	case v.Page == "":
+		data = site
+	case strings.HasPrefix(v.Page, "/"):
+		d, found := site.URLPage(v.Page)
+		if !found {
+			return nil, fmt.Errorf("no page with URL %s", v.Page)
+		}
+		data = d
+	default:
+		d, found := site.FilePathPage(v.Page)
+		if !found {
+			return nil, fmt.Errorf("no page with file %s", v.Page)
+		}
+		data = d
+	}
+	var props []string
+	if v.Key != "" {
+		props = strings.Split(v.Key, ".")
+	}
+	data, err := utils.FollowDots(data, props)
+	if err != nil {
+		return nil, err
+	}
+	root := map[bool]string{true: "site", false: v.Page}[v.Page == ""]
+	v.Parents = append(v.Parents, dashboardLink{root, href("")})
+	for i, p := range props {
+		v.Parents = append(v.Parents, dashboardLink{p, href(strings.Join(props[:i+1], "."))})
+	}
+	v.Value = inspectValue(data, 3)
+	child := func(name string) string {
+		return href(strings.TrimPrefix(v.Key+"."+name, "."))
+	}
+	switch rv := reflect.ValueOf(liquid.FromDrop(data)); rv.Kind() {
+	case reflect.Map:
+		for _, k := range rv.MapKeys() {
+			name := fmt.Sprint(k.Interface())
+			v.Entries = append(v.Entries, dashboardVariable{name, child(name), summarizeValue(rv.MapIndex(k).Interface())})
+		}
+		sort.Slice(v.Entries, func(i, j int) bool { return v.Entries[i].Name < v.Entries[j].Name })
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			name := strconv.Itoa(i)
+			v.Entries = append(v.Entries, dashboardVariable{name, child(name), summarizeValue(rv.Index(i).Interface())})
+		}
+	}
+	return v, nil
+}
+
+// inspectValue returns a JSON-encodable version of a variable's value.
+// Maps and lists below depth are replaced by their summaries.
+func inspectValue(value interface{}, depth int) interface{} {
+	value = liquid.FromDrop(value)
+	switch value := value.(type) {
+	case nil, bool, string, int, float64, time.Time:
+		return value
+	case []byte:
+		return string(value)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if depth == 0 {
+			return summarizeValue(value)
+		}
+		m := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			m[fmt.Sprint(k.Interface())] = inspectValue(rv.MapIndex(k).Interface(), depth-1)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if depth == 0 {
+			return summarizeValue(value)
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = inspectValue(rv.Index(i).Interface(), depth-1)
+		}
+		return items
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// summarizeValue returns a one-line description of a value.
+func summarizeValue(value interface{}) string {
+	value = liquid.FromDrop(value)
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		return fmt.Sprintf("{%d keys}", rv.Len())
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("[%d items]", rv.Len())
+	case reflect.String:
+		s := rv.String()
+		if len(s) > 200 {
+			s = s[:200] + "…"
+		}
+		return strconv.Quote(s)
+	case reflect.Invalid:
+		return "nil"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+type dashboardTiming struct {
+	URL          string  `json:"url"`
+	Href         string  `json:"href"`
+	Milliseconds float64 `json:"ms"`
+}
+
+// dashboardTimings returns the render times of the pages that have been
+// checked or requested since the site was loaded, slowest first.
+func (s *Server) dashboardTimings() []dashboardTiming {
+	s.log.Lock()
+	defer s.log.Unlock()
+	timings := make([]dashboardTiming, 0, len(s.log.timings))
+	for u, d := range s.log.timings {
+		timings = append(timings, dashboardTiming{u, s.siteHref(u), float64(d) / float64(time.Millisecond)})
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		a, b := timings[i], timings[j]
+		return a.Milliseconds > b.Milliseconds || a.Milliseconds == b.Milliseconds && a.URL < b.URL
+	})
+	return timings
+}
+
+var dashboardTitles = map[string]string{
+	"index":     "Dashboard",
+	"routes":    "Routes",
+	"problems":  "Problems",
+	"variables": "Variables",
+	"timings":   "Timings",
+}
+
+var dashboardTemplates = map[string]*template.Template{}
+
+func init() {
+	// dashboard returns the path of a view, for the templates' links.
+	funcs := template.FuncMap{"dashboard": func(view string) string { return dashboardPath + view }}
+	layout := template.Must(template.New("layout").Funcs(funcs).Parse(dashboardLayout))
+	for name, src := range dashboardViews {
+		dashboardTemplates[name] = template.Must(template.Must(layout.Clone()).Parse(src))
+	}
+}
+
+const dashboardLayout = `<!DOCTYPE html>
+<html><head>
+	<meta charset="utf-8">
+	<title>{{ .Title }} · gojekyll</title>
+	<style type="text/css">
+		body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; margin: 2rem; line-height: 1.4; }
+		nav a { margin-right: 1em; }
+		table { border-collapse: collapse; }
+		td, th { text-align: left; padding: 2px 1em 2px 0; vertical-align: top; }
+		code, pre, .excerpt { font-family: Menlo, Consolas, monospace; }
+		.excerpt { background-color: black; color: rgb(232, 232, 232); padding: 1em; margin: 1ex 0 2ex; }
+		.line.error, .line.error .lineno { color: red; }
+		.lineno { color: #6D7891; border-right: 1px solid #6D7891; padding-right: 10px; margin: 0 10px 0 5px; display: inline-block; text-align: right; width: 3em; }
+		.muted { color: #6D7891; }
+	</style>
+</head>
+<body>
+	<nav>
+		<a href="{{ dashboard "" }}">Dashboard</a>
+		<a href="{{ dashboard "routes" }}">Routes</a>
+		<a href="{{ dashboard "problems" }}">Problems</a>
+		<a href="{{ dashboard "variables" }}">Variables</a>
+		<a href="{{ dashboard "timings" }}">Timings</a>
+		<a class="muted" href="{{ .JSON }}">JSON</a>
+	</nav>
+	<h1>{{ .Title }}</h1>
+	{{ template "content" .Data }}
+</body>
+</html>`
+
+var dashboardViews = map[string]string{
+	"index": `{{ define "content" }}
+	<p><a href="{{ .SiteURL }}">View the site</a></p>
+	<ul>
+		<li><a href="{{ dashboard "routes" }}">{{ .Routes }} routes</a></li>
+		<li><a href="{{ dashboard "problems" }}">{{ .Errors }} errors, {{ .Warnings }} warnings</a></li>
+	</ul>
+	{{ end }}`,
+	"routes": `{{ define "content" }}
+	<table>
+		<tr><th>URL</th><th>Source</th><th></th></tr>
+		{{ range . }}
+		<tr>
+			<td><a href="{{ .Href }}">{{ .URL }}</a></td>
+			<td>{{ if .Source }}{{ .Source }}{{ else }}<span class="muted">generated</span>{{ end }}</td>
+			<td>{{ if not .Static }}<a href="{{ dashboard "variables" }}?page={{ .URL }}">variables</a>{{ end }}</td>
+		</tr>
+		{{ end }}
+	</table>
+	{{ end }}`,
+	"problems": `{{ define "content" }}
+	{{ if .Checking }}<p class="muted">Checking the site… Reload this page to see the results.</p>{{ end }}
+	<h2>Errors</h2>
+	{{ range .Errors }}
+		<div>{{ if .URL }}<a href="{{ .Href }}"><code>{{ .URL }}</code></a>: {{ end }}{{ .Message }}</div>
+		{{ if .Excerpt }}<div class="excerpt">{{ .Excerpt }}</div>{{ end }}
+	{{ else }}
+		<p class="muted">No errors.</p>
+	{{ end }}
+	<h2>Warnings</h2>
+	<ul>
+	{{ range .Warnings }}<li>{{ . }}</li>{{ else }}<p class="muted">None.</p>{{ end }}
+	</ul>
+	{{ end }}`,
+	"variables": `{{ define "content" }}
+	<p>{{ range $i, $p := .Parents }}{{ if $i }} . {{ end }}<a href="{{ $p.Href }}">{{ $p.Name }}</a>{{ end }}</p>
+	{{ if .Entries }}
+	<table>
+		{{ range .Entries }}
+		<tr><td><a href="{{ .Href }}">{{ .Name }}</a></td><td><code>{{ .Summary }}</code></td></tr>
+		{{ end }}
+	</table>
+	{{ else }}
+	<pre>{{ printf "%v" .Value }}</pre>
+	{{ end }}
+	{{ end }}`,
+	"timings": `{{ define "content" }}
+	<table>
+		<tr><th>URL</th><th>Render time</th></tr>
+		{{ range . }}
+		<tr><td><a href="{{ .Href }}">{{ .URL }}</a></td><td>{{ printf "%.1f" .Milliseconds }} ms</td></tr>
+		{{ else }}
+		<tr><td colspan="2" class="muted">No pages have been rendered since the site was loaded.</td></tr>
+		{{ end }}
+	</table>
+	{{ end }}`,
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/site"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_dashboard(t *testing.T) {
+	s := newTestServer(t)
+
+	w := serveRequest(s, "GET", "/__gojekyll", nil)
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, "/__gojekyll/", w.Header().Get("Location"))
+
+	for _, view := range []string{"", "routes", "problems", "variables", "timings"} {
+		w = serveRequest(s, "GET", "/__gojekyll/"+view, nil)
+		require.Equal(t, http.StatusOK, w.Code, view)
+		require.Contains(t, w.Header().Get("Content-Type"), "text/html", view)
+		require.Contains(t, w.Body.String(), `<a href="/__gojekyll/routes">Routes</a>`, view)
+		w = serveRequest(s, "GET", "/__gojekyll/"+view+".json", nil)
+		require.Equal(t, http.StatusOK, w.Code, view)
+		require.True(t, json.Valid(w.Body.Bytes()), view)
+	}
+	w = serveRequest(s, "GET", "/__gojekyll/missing", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServer_dashboardRoutes(t *testing.T) {
+	s := newTestServer(t)
+	var routes []dashboardRoute
+	w := serveRequest(s, "GET", "/__gojekyll/routes.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &routes))
+	require.Contains(t, routes, dashboardRoute{URL: "/about.html", Href: "/docs/about.html", Source: "about.html"})
+	require.Contains(t, routes, dashboardRoute{URL: "/assets/style.css", Href: "/docs/assets/style.css", Source: "assets/style.css", Static: true})
+
+	w = serveRequest(s, "GET", "/__gojekyll/routes", nil)
+	require.Contains(t, w.Body.String(), `<a href="/__gojekyll/variables?page=%2fabout.html">variables</a>`)
+}
+
+func TestServer_dashboardVariables(t *testing.T) {
+	s := newTestServer(t)
+	var v struct{ Value interface{} }
+	w := serveRequest(s, "GET", "/__gojekyll/variables.json?page=/about.html&key=redirect_from", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	require.Equal(t, "/old.html", v.Value)
+
+	w = serveRequest(s, "GET", "/__gojekyll/variables?key=html_pages", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `href="/__gojekyll/variables?key=html_pages.0"`)
+
+	w = serveRequest(s, "GET", "/__gojekyll/variables.json?key=missing", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = serveRequest(s, "GET", "/__gojekyll/variables.json?page=/missing.html", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServer_dashboardProblemsAndTimings(t *testing.T) {
+	s := newTestServer(t)
+	serveRequest(s, "GET", "/docs/about.html", nil)
+	s.log.rendered("/broken.html", 0, errors.New("render error"))
+
+	var timings []dashboardTiming
+	w := serveRequest(s, "GET", "/__gojekyll/timings.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &timings))
+	require.Len(t, timings, 2)
+
+	var problems dashboardProblems
+	w = serveRequest(s, "GET", "/__gojekyll/problems.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problems))
+	require.Len(t, problems.Errors, 1)
+	require.Equal(t, "/docs/broken.html", problems.Errors[0].Href)
+	require.Equal(t, "render error", problems.Errors[0].Message)
+
+	s.log.reset()
+	w = serveRequest(s, "GET", "/__gojekyll/problems.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problems))
+	require.Empty(t, problems.Errors)
+}
+
+func TestServer_checkSite(t *testing.T) {
+	site, err := site.FromDirectory("testdata/broken", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, site.Read())
+	s := &Server{Site: site}
+	var problems dashboardProblems
+	w := serveRequest(s, "GET", "/__gojekyll/problems.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problems))
+	require.True(t, problems.Checking)
+
+	// the check finds the error in a page that hasn't been requested
+	s.checkSite(s.Site, s.log.currentGeneration())
+	w = serveRequest(s, "GET", "/__gojekyll/problems.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problems))
+	require.False(t, problems.Checking)
+	require.Len(t, problems.Errors, 1)
+	require.Equal(t, "/broken.html", problems.Errors[0].URL)
+	require.Contains(t, problems.Errors[0].Message, "no_such_filter")
+
+	// a check of a site that has since been replaced isn't recorded
+	generation := s.log.currentGeneration()
+	s.log.reset()
+	s.checkSite(s.Site, generation)
+	w = serveRequest(s, "GET", "/__gojekyll/problems.json", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problems))
+	require.True(t, problems.Checking)
+	require.Empty(t, problems.Errors)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha1" // nolint: gas
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osteele/gojekyll/pages"
+)
+
+// A renderCache holds the rendered output of the site's documents, keyed by
+// URL. Handlers read and fill it while they hold the server's read lock; a
+// reload clears it while it holds the write lock.
+type renderCache struct {
+	sync.Mutex
+	docs map[string]*renderedDocument
+}
+
+// A renderedDocument is a document's output, with the Live Reload script
+// injected into HTML.
+type renderedDocument struct {
+	contentType string
+	body        []byte
+	etag        string // "" if rendering failed; body is the error page
+}
+
+func (c *renderCache) get(url string) (*renderedDocument, bool) {
+	c.Lock()
+	defer c.Unlock()
+	d, found := c.docs[url]
+	return d, found
+}
+
+func (c *renderCache) set(url string, d *renderedDocument) {
+	c.Lock()
+	defer c.Unlock()
+	if c.docs == nil {
+		c.docs = map[string]*renderedDocument{}
+	}
+	c.docs[url] = d
+}
+
+func (c *renderCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.docs = nil
+}
+
+// documentHandler returns a handler that writes the document with the status
+// code. A successful response has an ETag, and honors conditional and range
+// requests.
+func (s *Server) documentHandler(p pages.Document, status int) http.HandlerFunc {
+	contentType := s.contentType(p.OutputExt())
+	if p.IsStatic() {
+		return staticFileHandler(p.Source(), contentType, status)
+	}
+	d := s.renderDocument(p, contentType)
+	if d.etag == "" && status == http.StatusOK {
+		status = http.StatusInternalServerError
+	}
+	return func(rw http.ResponseWriter, r *http.Request) {
+		setContentType(rw.Header(), d.contentType)
+		if status != http.StatusOK {
+			writeWithStatus(rw, r, status, bytes.NewReader(d.body), int64(len(d.body)))
+			return
+		}
+		rw.Header().Set("ETag", d.etag)
+		http.ServeContent(rw, r, "", time.Time{}, bytes.NewReader(d.body))
+	}
+}
+
+// renderDocument returns the document's output from the cache, or renders
+// it. Failed renders aren't cached, so that the error page is replaced once
+// the error is fixed.
+func (s *Server) renderDocument(p pages.Document, contentType string) *renderedDocument {
+	if d, found := s.cache.get(p.URL()); found {
+		return d
+	}
+	buf := new(bytes.Buffer)
+	var w io.Writer = buf
+	if strings.HasPrefix(contentType, "text/html") {
+		w = s.liveReloadInjector(w)
+	}
+	start := time.Now()
+	err := s.Site.WriteDocument(w, p)
+	s.log.rendered(p.URL(), time.Since(start), err)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering %s: %s\n", p.URL(), err)
+		buf.Reset()
+		s.writeRenderError(w, err)
+		return &renderedDocument{contentType: contentType, body: buf.Bytes()}
+	}
+	d := &renderedDocument{contentType, buf.Bytes(), fmt.Sprintf(`"%x"`, sha1.Sum(buf.Bytes()))} // nolint: gas
+	s.cache.set(p.URL(), d)
+	return d
+}
+
+// staticFileHandler returns a handler that copies a static file. Its ETag is
+// computed from its modification time and size, so that the file isn't read
+// to answer a conditional request.
+func staticFileHandler(filename, contentType string, status int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		f, err := os.Open(filename)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close() // nolint: errcheck, gas
+		info, err := f.Stat()
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		setContentType(rw.Header(), contentType)
+		if status != http.StatusOK {
+			writeWithStatus(rw, r, status, f, info.Size())
+			return
+		}
+		rw.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+		http.ServeContent(rw, r, filename, info.ModTime(), f)
+	}
+}
+
+// writeWithStatus writes an unconditional response, such as a 404 page.
+func writeWithStatus(rw http.ResponseWriter, r *http.Request, status int, body io.Reader, size int64) {
+	rw.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	rw.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(rw, body); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+	}
+}
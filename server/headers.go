@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/osteele/gojekyll/utils"
+)
+
+// builtinMimeTypes are content types that the host's mime database may lack
+// or get wrong. The site's mime_types override these.
+var builtinMimeTypes = map[string]string{
+	".avif":        "image/avif",
+	".map":         "application/json",
+	".mjs":         "text/javascript; charset=utf-8",
+	".wasm":        "application/wasm",
+	".webmanifest": "application/manifest+json",
+	".webp":        "image/webp",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+}
+
+// contentType returns the content type for a file extension, from the site's
+// mime_types, the built-in types, or the host's mime database.
+func (s *Server) contentType(ext string) string {
+	ext = strings.ToLower(ext)
+	for k, t := range s.Site.Config().MimeTypes {
+		if strings.ToLower("."+strings.TrimPrefix(k, ".")) == ext {
+			return t
+		}
+	}
+	if t, found := builtinMimeTypes[ext]; found {
+		return t
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// setContentType sets the Content-Type header, unless the site's headers
+// have already set it.
+func setContentType(h http.Header, contentType string) {
+	if contentType != "" && h.Get("Content-Type") == "" {
+		h.Set("Content-Type", contentType)
+	}
+}
+
+// A headerRule is a block of a _headers file: the headers for the URL paths
+// that match a pattern.
+type headerRule struct {
+	pattern *regexp.Regexp
+	fields  []headerField
+}
+
+type headerField struct{ name, value string }
+
+// headersFileName is the name of the headers file in the source directory.
+// Include it in the site (include: [_headers]) to publish it to Netlify.
+const headersFileName = "_headers"
+
+// readHeadersFile reads a headers file, if it exists. The syntax is
+// Netlify's: a line that begins with a URL path pattern is followed by
+// indented "Name: value" lines. In a pattern, * matches any text, and a
+// :placeholder matches a path segment.
+//
+//	/assets/*
+//	  Cache-Control: public, max-age=31536000
+//	/*
+//	  X-Frame-Options: DENY
+func readHeadersFile(filename string) ([]headerRule, error) {
+	f, err := os.Open(filename)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck, gas
+	rules, err := parseHeadersFile(f)
+	return rules, utils.WrapPathError(err, filename)
+}
+
+func parseHeadersFile(r io.Reader) ([]headerRule, error) {
+	var (
+		rules   []headerRule
+		scanner = bufio.NewScanner(r)
+		lineNo  = 0
+	)
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", strings.HasPrefix(trimmed, "#"):
+		case trimmed == line:
+			if !strings.HasPrefix(line, "/") {
+				return nil, fmt.Errorf("line %d: the path %q doesn't begin with /", lineNo, line)
+			}
+			rules = append(rules, headerRule{pattern: headerPathPattern(line)})
+		case len(rules) == 0:
+			return nil, fmt.Errorf("line %d: a header precedes the first path", lineNo)
+		default:
+			i := strings.Index(trimmed, ":")
+			if i <= 0 {
+				return nil, fmt.Errorf("line %d: expected Name: value", lineNo)
+			}
+			rule := &rules[len(rules)-1]
+			rule.fields = append(rule.fields, headerField{strings.TrimSpace(trimmed[:i]), strings.TrimSpace(trimmed[i+1:])})
+		}
+	}
+	return rules, scanner.Err()
+}
+
+var headerPatternTokenRE = regexp.MustCompile(`\\\*|:[A-Za-z_]\w*`)
+
+// headerPathPattern returns a regular expression that matches the URL paths
+// that a _headers path pattern applies to.
+func headerPathPattern(path string) *regexp.Regexp {
+	expr := headerPatternTokenRE.ReplaceAllStringFunc(regexp.QuoteMeta(path), func(m string) string {
+		if m == `\*` {
+			return ".*"
+		}
+		return "[^/]+"
+	})
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// setSiteHeaders sets the webrick.headers from the site configuration, and
+// the headers from the _headers file whose patterns match the site-relative
+// URL path. A _headers header replaces a webrick header with the same name;
+// the values of a header that's in several matching rules are combined.
+func (s *Server) setSiteHeaders(h http.Header, urlpath string) {
+	for k, v := range s.Site.Config().Webrick.Headers {
+		h.Set(k, v)
+	}
+	set := map[string]bool{}
+	for _, rule := range s.headers {
+		if !rule.pattern.MatchString(urlpath) {
+			continue
+		}
+		for _, f := range rule.fields {
+			name := http.CanonicalHeaderKey(f.name)
+			if set[name] {
+				h.Add(name, f.value)
+			} else {
+				h.Set(name, f.value)
+				set[name] = true
+			}
+		}
+	}
+}
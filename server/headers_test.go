@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeadersFile(t *testing.T) {
+	rules, err := parseHeadersFile(strings.NewReader("# comment\n/a/*\n  X-A: 1\n  X-B: b: c\n\n/b\n"))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, []headerField{{"X-A", "1"}, {"X-B", "b: c"}}, rules[0].fields)
+	require.Empty(t, rules[1].fields)
+
+	_, err = parseHeadersFile(strings.NewReader("  X-A: 1\n/a\n"))
+	require.Error(t, err)
+	_, err = parseHeadersFile(strings.NewReader("a/*\n  X-A: 1\n"))
+	require.Error(t, err)
+	_, err = parseHeadersFile(strings.NewReader("/a\n  X-A\n"))
+	require.Error(t, err)
+}
+
+func TestHeaderPathPattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"/*", "/", true},
+		{"/*", "/a/b.html", true},
+		{"/assets/*", "/assets/css/main.css", true},
+		{"/assets/*", "/about.html", false},
+		{"/:name.html", "/about.html", true},
+		{"/:name.html", "/a/about.html", false},
+		{"/a.b", "/aXb", false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.match, headerPathPattern(test.pattern).MatchString(test.path), test)
+	}
+}
+
+func TestServer_headers(t *testing.T) {
+	s := newTestServer(t)
+	rules, err := readHeadersFile("testdata/site/_headers")
+	require.NoError(t, err)
+	s.headers = rules
+
+	w := serveRequest(s, "GET", "/docs/about.html", nil)
+	require.Equal(t, "gojekyll", w.Header().Get("X-Served-By"))
+	require.Equal(t, []string{"DENY"}, w.Header()["X-Frame-Options"])
+	require.Equal(t, []string{"</assets/style.css>; rel=preload", "</about.html>; rel=prefetch"}, w.Header()["Link"])
+	require.Empty(t, w.Header().Get("Cache-Control"))
+
+	w = serveRequest(s, "GET", "/docs/assets/style.css", nil)
+	require.Equal(t, "public, max-age=31536000", w.Header().Get("Cache-Control"))
+
+	w = serveRequest(s, "GET", "/docs/missing", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+
+	// headers aren't added outside the site
+	w = serveRequest(s, "GET", "/__gojekyll/routes.json", nil)
+	require.Empty(t, w.Header().Get("X-Served-By"))
+
+	rules, err = readHeadersFile("testdata/site/missing")
+	require.NoError(t, err)
+	require.Nil(t, rules)
+}
+
+func TestServer_contentType(t *testing.T) {
+	s := newTestServer(t)
+	w := serveRequest(s, "GET", "/docs/assets/app.webmanifest", nil)
+	require.Equal(t, "application/manifest+json", w.Header().Get("Content-Type"))
+	w = serveRequest(s, "GET", "/docs/notes.txt", nil)
+	require.Equal(t, "text/x-test", w.Header().Get("Content-Type"))
+	w = serveRequest(s, "GET", "/docs/about.html", nil)
+	require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+}
@@ -1,19 +1,33 @@
 package server
 
 import (
+	"fmt"
+	"html"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/jaschaephraim/lrserver"
 )
 
-// liveReloadScriptTag is inserted into the HTML page.
-var liveReloadScriptTag = []byte(`<script src="http://localhost:35729/livereload.js"></script>`)
+// liveReloadScriptPath is where the server serves the Live Reload JavaScript.
+// It's under the dashboard path, so that it doesn't hide a site file.
+const liveReloadScriptPath = dashboardPath + "livereload.js"
+
+// liveReloadSocketPath is where the Live Reload JavaScript opens its
+// websocket, if it connects to the server's own origin. The script chooses
+// this path, so it can't move under the dashboard path; instead, the server
+// only forwards it if the site doesn't have a page there.
+const liveReloadSocketPath = "/livereload"
 
 // startLiveReloader starts the Live Reload server as a go routine, and returns immediately
 func (s *Server) startLiveReloader() error {
-	lr := lrserver.New(lrserver.DefaultName, lrserver.DefaultPort)
+	lr := lrserver.New(lrserver.DefaultName, uint16(s.Site.Config().LiveReloadPort))
 	s.lr = lr
 	lr.SetStatusLog(nil)
 	lr.ErrorLog().SetOutput(outputFilter{os.Stdout})
@@ -21,10 +35,84 @@ func (s *Server) startLiveReloader() error {
 	return nil
 }
 
-// NewLiveReloadInjector returns a writer that injects the Live Reload JavaScript
-// into its wrapped content.
-func NewLiveReloadInjector(w io.Writer) io.Writer {
-	return TagInjector{w, liveReloadScriptTag}
+// liveReloadScriptHandler serves the Live Reload JavaScript from the server's
+// own origin, so that it loads from whichever host the browser used to reach
+// the site.
+func liveReloadScriptHandler(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/javascript")
+	if _, err := io.WriteString(rw, lrserver.JS); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+	}
+}
+
+// liveReloadProxy forwards Live Reload websocket connections from the
+// server's origin to the Live Reload server. The server uses this over HTTPS,
+// where the page can only open a secure websocket, and the Live Reload server
+// only accepts insecure ones.
+func (s *Server) liveReloadProxy() http.Handler {
+	target := fmt.Sprintf("127.0.0.1:%d", s.Site.Config().LiveReloadPort)
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !isUpgrade(r) {
+			http.NotFound(rw, r)
+			return
+		}
+		out := outgoingRequest(r)
+		out.URL.Scheme, out.URL.Host = "http", target
+		out.Host = target
+		if err := tunnel(rw, out); err != nil {
+			logProxyError(r, target, err)
+			http.Error(rw, badGatewayMessage(err), http.StatusBadGateway)
+		}
+	})
+}
+
+// liveReloadScriptTag returns the tag that is inserted into HTML pages. Its
+// query tells the script where to open the Live Reload websocket: on the
+// page's host unless livereload_host is set, and on livereload_port. Over
+// HTTPS, the websocket is on the page's port, and the server forwards it to
+// the Live Reload server.
+func (s *Server) liveReloadScriptTag() []byte {
+	cfg := s.Site.Config()
+	q := url.Values{"port": {strconv.Itoa(cfg.LiveReloadPort)}}
+	if s.https {
+		// An empty port tells the script to use the page's port.
+		q.Set("port", "")
+	}
+	if cfg.LiveReloadHost != "" {
+		q.Set("host", cfg.LiveReloadHost)
+	}
+	return []byte(fmt.Sprintf(`<script src="%s?%s"></script>`, liveReloadScriptPath, html.EscapeString(q.Encode())))
+}
+
+// liveReloadInjector returns a writer that injects the Live Reload JavaScript
+// into its wrapped content, if the server is watching the site.
+func (s *Server) liveReloadInjector(w io.Writer) io.Writer {
+	if !s.Site.Config().Watch {
+		return w
+	}
+	return TagInjector{w, s.liveReloadScriptTag()}
+}
+
+// reloadURLs tells the browsers to reload the URLs, which are relative to
+// the site base. A browser swaps in a changed stylesheet without reloading
+// the page; any other change reloads the page.
+func (s *Server) reloadURLs(urls map[string]bool) {
+	baseurl := strings.TrimSuffix(s.Site.Config().BaseURL, "/")
+	for u := range urls {
+		s.lr.Reload(baseurl + u)
+	}
+}
+
+// isStylesheetChange returns true if the changed files are all stylesheets.
+func isStylesheetChange(paths []string) bool {
+	for _, p := range paths {
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".css", ".scss", ".sass":
+		default:
+			return false
+		}
+	}
+	return len(paths) > 0
 }
 
 // Remove the lines that match the exclusion pattern.
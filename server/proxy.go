@@ -0,0 +1,226 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/config"
+)
+
+// A proxyRoute forwards requests whose path begins with prefix, as
+// configured by serve.proxy.
+type proxyRoute struct {
+	prefix  string
+	handler http.Handler
+}
+
+// newProxyRoutes creates the proxy routes from the site configuration.
+func newProxyRoutes(cfg *config.Config) ([]proxyRoute, error) {
+	routes := make([]proxyRoute, 0, len(cfg.Serve.Proxy))
+	for prefix, rule := range cfg.Serve.Proxy {
+		h, err := newReverseProxy(prefix, rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, proxyRoute{prefix, h})
+	}
+	// Longer prefixes take precedence.
+	sort.Slice(routes, func(i, j int) bool { return len(routes[i].prefix) > len(routes[j].prefix) })
+	return routes, nil
+}
+
+// proxyHandler returns the proxy route for a request path, or nil. It's
+// called while the server holds the read lock.
+func (s *Server) proxyHandler(urlpath string) http.Handler {
+	for _, p := range s.proxies {
+		if strings.HasPrefix(urlpath, p.prefix) {
+			return p.handler
+		}
+	}
+	return nil
+}
+
+func newReverseProxy(prefix string, rule config.ProxyRule) (http.Handler, error) {
+	target, err := url.Parse(rule.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("serve.proxy %s: invalid target %q", prefix, rule.Target)
+	}
+	base := target.Scheme + "://" + target.Host + strings.TrimSuffix(target.Path, "/")
+	director := func(r *http.Request) {
+		urlpath := r.URL.Path
+		if rule.StripPrefix {
+			urlpath = "/" + strings.TrimPrefix(strings.TrimPrefix(urlpath, prefix), "/")
+		}
+		r.Header.Set("X-Forwarded-Host", r.Host)
+		r.Header.Set("X-Forwarded-Proto", map[bool]string{true: "https", false: "http"}[r.TLS != nil])
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		r.URL.Path = strings.TrimSuffix(target.Path, "/") + urlpath
+		r.URL.RawPath = ""
+		r.Host = target.Host
+		setHeaders(r.Header, rule.Headers)
+	}
+	// A redirect to the upstream server is rewritten to a redirect to this one.
+	modifyResponse := func(resp *http.Response) error {
+		if loc := resp.Header.Get("Location"); strings.HasPrefix(loc, base+"/") {
+			loc = strings.TrimPrefix(loc, base)
+			if rule.StripPrefix {
+				loc = strings.TrimSuffix(prefix, "/") + loc
+			}
+			resp.Header.Set("Location", loc)
+		}
+		setHeaders(resp.Header, rule.ResponseHeaders)
+		return nil
+	}
+	proxy := &httputil.ReverseProxy{
+		Director:       director,
+		ModifyResponse: modifyResponse,
+		Transport:      proxyTransport{rule.Target},
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !isUpgrade(r) {
+			proxy.ServeHTTP(rw, r)
+			return
+		}
+		out := outgoingRequest(r)
+		director(out)
+		if err := tunnel(rw, out); err != nil {
+			logProxyError(r, rule.Target, err)
+			http.Error(rw, badGatewayMessage(err), http.StatusBadGateway)
+		}
+	}), nil
+}
+
+// proxyTransport is the transport for a reverse proxy. If the upstream server
+// can't be reached, it logs the error and responds with a 502 that includes
+// it, where httputil.ReverseProxy would respond with an empty body.
+type proxyTransport struct{ target string }
+
+func (t proxyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err == nil {
+		return resp, nil
+	}
+	logProxyError(r, t.target, err)
+	msg := badGatewayMessage(err) + "\n"
+	return &http.Response{
+		Status:     "502 Bad Gateway",
+		StatusCode: http.StatusBadGateway,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":           {"text/plain; charset=utf-8"},
+			"X-Content-Type-Options": {"nosniff"},
+		},
+		Body:          ioutil.NopCloser(strings.NewReader(msg)),
+		ContentLength: int64(len(msg)),
+		Request:       r,
+	}, nil
+}
+
+func logProxyError(r *http.Request, target string, err error) {
+	fmt.Fprintf(os.Stderr, "Error proxying %s to %s: %s\n", r.URL.Path, target, err)
+}
+
+func badGatewayMessage(err error) string {
+	return fmt.Sprintf("502 bad gateway: %s", err)
+}
+
+// isUpgrade returns true if the request asks to switch protocols, as a
+// websocket request does.
+func isUpgrade(r *http.Request) bool {
+	if r.Header.Get("Upgrade") == "" {
+		return false
+	}
+	for _, v := range r.Header["Connection"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// outgoingRequest returns a copy of r that a proxy can modify.
+func outgoingRequest(r *http.Request) *http.Request {
+	out := new(http.Request)
+	*out = *r
+	u := *r.URL
+	out.URL = &u
+	out.Header = make(http.Header, len(r.Header))
+	for k, v := range r.Header {
+		out.Header[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+// tunnel sends r, whose URL is on the upstream server, to that server; and
+// then copies bytes in both directions between the client and the upstream
+// connections, until either side closes. It forwards the requests that switch
+// protocols, which httputil.ReverseProxy only does from Go 1.12. It returns
+// an error, and doesn't take over the connection, if it can't reach the
+// upstream server.
+func tunnel(rw http.ResponseWriter, r *http.Request) error {
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		return fmt.Errorf("the connection doesn't support switching protocols")
+	}
+	secure := r.URL.Scheme == "https" || r.URL.Scheme == "wss"
+	addr := r.URL.Host
+	if r.URL.Port() == "" {
+		addr = net.JoinHostPort(r.URL.Hostname(), map[bool]string{true: "443", false: "80"}[secure])
+	}
+	var (
+		upstream net.Conn
+		err      error
+	)
+	if secure {
+		upstream, err = tls.Dial("tcp", addr, &tls.Config{ServerName: r.URL.Hostname()})
+	} else {
+		upstream, err = net.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if err = r.Write(upstream); err != nil {
+		upstream.Close() // nolint: errcheck, gas
+		return err
+	}
+	client, buf, err := hj.Hijack()
+	if err != nil {
+		upstream.Close() // nolint: errcheck, gas
+		return err
+	}
+	done := make(chan struct{}, 2)
+	copyConn := func(dst io.Writer, src io.Reader) {
+		io.Copy(dst, src) // nolint: errcheck, gas
+		done <- struct{}{}
+	}
+	go copyConn(upstream, buf.Reader)
+	go copyConn(client, upstream)
+	<-done
+	client.Close()   // nolint: errcheck, gas
+	upstream.Close() // nolint: errcheck, gas
+	return nil
+}
+
+// setHeaders sets the headers in h. An empty value removes the header.
+func setHeaders(h http.Header, values map[string]string) {
+	for k, v := range values {
+		if v == "" {
+			h.Del(k)
+		} else {
+			h.Set(k, v)
+		}
+	}
+}
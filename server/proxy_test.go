@@ -0,0 +1,129 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/osteele/gojekyll/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newProxyTestServer(t *testing.T, rules map[string]config.ProxyRule) *Server {
+	s := newTestServer(t)
+	s.Site.Config().Serve.Proxy = rules
+	proxies, err := newProxyRoutes(s.Site.Config())
+	require.NoError(t, err)
+	s.proxies = proxies
+	return s
+}
+
+func TestServer_proxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/redirect" {
+			http.Redirect(rw, r, "http://"+r.Host+"/v1/target", http.StatusFound)
+			return
+		}
+		rw.Header().Set("X-Upstream", "yes")
+		fmt.Fprintf(rw, "%s %s dev=%s cookie=%s", r.Method, r.URL.Path, r.Header.Get("X-Dev"), r.Header.Get("Cookie"))
+	}))
+	defer upstream.Close()
+	s := newProxyTestServer(t, map[string]config.ProxyRule{
+		"/api/":    {Target: upstream.URL},
+		"/api/v1/": {Target: upstream.URL + "/v1", StripPrefix: true, Headers: map[string]string{"X-Dev": "1", "Cookie": ""}, ResponseHeaders: map[string]string{"X-Upstream": "", "Access-Control-Allow-Origin": "*"}},
+	})
+
+	w := serveRequest(s, "POST", "/api/users", http.Header{"Cookie": {"a=b"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "POST /api/users dev= cookie=a=b", w.Body.String())
+	require.Equal(t, "yes", w.Header().Get("X-Upstream"))
+
+	w = serveRequest(s, "GET", "/api/v1/users", http.Header{"Cookie": {"a=b"}})
+	require.Equal(t, "GET /v1/users dev=1 cookie=", w.Body.String())
+	require.Equal(t, "", w.Header().Get("X-Upstream"))
+	require.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = serveRequest(s, "GET", "/api/v1/redirect", nil)
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, "/api/v1/target", w.Header().Get("Location"))
+
+	// other paths are served from the site
+	w = serveRequest(s, "GET", "/docs/about.html", nil)
+	require.Contains(t, w.Body.String(), "about")
+}
+
+func TestServer_proxyUnavailable(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+	s := newProxyTestServer(t, map[string]config.ProxyRule{"/api/": {Target: upstream.URL}})
+	w := serveRequest(s, "GET", "/api/users", nil)
+	require.Equal(t, http.StatusBadGateway, w.Code)
+	require.Contains(t, w.Body.String(), "502 bad gateway")
+}
+
+// newWebsocketServer starts a server that replies to a websocket message
+// with the request path and the message.
+func newWebsocketServer() *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(rw, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close() // nolint: errcheck
+		mt, msg, err := conn.ReadMessage()
+		if err == nil {
+			_ = conn.WriteMessage(mt, []byte(r.URL.Path+": "+string(msg)))
+		}
+	}))
+}
+
+func requireWebsocketReply(t *testing.T, url, expected string) {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close() // nolint: errcheck
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, expected, string(msg))
+}
+
+func TestServer_proxyWebsocket(t *testing.T) {
+	upstream := newWebsocketServer()
+	defer upstream.Close()
+	s := newProxyTestServer(t, map[string]config.ProxyRule{"/socket/": {Target: upstream.URL, StripPrefix: true}})
+	front := httptest.NewServer(http.HandlerFunc(s.handler))
+	defer front.Close()
+	requireWebsocketReply(t, front.URL+"/socket/chat", "/chat: hello")
+}
+
+func TestServer_liveReloadProxy(t *testing.T) {
+	upstream := newWebsocketServer()
+	defer upstream.Close()
+	port, err := strconv.Atoi(upstream.URL[strings.LastIndex(upstream.URL, ":")+1:])
+	require.NoError(t, err)
+	s := newTestServer(t)
+	s.Site.Config().LiveReloadPort = port
+	front := httptest.NewServer(s.liveReloadProxy())
+	defer front.Close()
+	requireWebsocketReply(t, front.URL+"/livereload", "/livereload: hello")
+
+	upstream.Close()
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/livereload", nil)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	s.liveReloadProxy().ServeHTTP(w, r)
+	require.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestNewProxyRoutes(t *testing.T) {
+	cfg := config.Default()
+	cfg.Serve.Proxy = map[string]config.ProxyRule{"/api/": {Target: "localhost:8080"}}
+	_, err := newProxyRoutes(&cfg)
+	require.Error(t, err)
+}
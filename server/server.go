@@ -6,96 +6,215 @@ import (
 	"html"
 	"io"
 	"io/ioutil"
-	"mime"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/jaschaephraim/lrserver"
+	"github.com/osteele/gojekyll/pages"
 	"github.com/osteele/gojekyll/site"
 	"github.com/osteele/liquid"
 	"github.com/pkg/browser"
 )
 
-// Server serves the site on HTTP.
+// Server serves the site on HTTP, or on HTTPS if it's configured with a
+// certificate.
 type Server struct {
-	sync.Mutex
-	Site *site.Site
-	lr   *lrserver.Server
+	sync.RWMutex
+	Site    *site.Site
+	lr      *lrserver.Server
+	cache   renderCache
+	log     buildLog
+	proxies []proxyRoute
+	headers []headerRule // from the site's _headers file
+	https   bool
+	lrProxy http.Handler // forwards the Live Reload websocket, over HTTPS
 }
 
 // Run runs the server.
 func (s *Server) Run(open bool, logger func(label, value string)) error {
 	cfg := s.Site.Config()
 	s.Site.SetAbsoluteURL("")
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+	if s.proxies, err = newProxyRoutes(cfg); err != nil {
+		return err
+	}
+	if s.headers, err = readHeadersFile(filepath.Join(s.Site.SourceDir(), headersFileName)); err != nil {
+		return err
+	}
+	s.https = tlsConfig != nil
+	scheme := map[bool]string{true: "https", false: "http"}[s.https]
 	address := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
-	logger("Server address:", "http://"+address+"/")
+	siteURL := scheme + "://" + address + strings.TrimSuffix(cfg.BaseURL, "/") + "/"
+	logger("Server address:", siteURL)
 	if cfg.Watch {
 		if err := s.startLiveReloader(); err != nil {
 			return err
 		}
+		if s.https {
+			s.lrProxy = s.liveReloadProxy()
+		} else {
+			logger("LiveReload port:", strconv.Itoa(cfg.LiveReloadPort))
+		}
 		if err := s.watchReload(); err != nil {
 			return err
 		}
 	}
+	go s.checkSite(s.Site, s.log.currentGeneration())
 	http.HandleFunc("/", s.handler)
+	srv := &http.Server{Addr: address, TLSConfig: tlsConfig}
 	c := make(chan error)
 	go func() {
-		c <- http.ListenAndServe(address, nil)
+		if s.https {
+			c <- srv.ListenAndServeTLS("", "")
+		} else {
+			c <- srv.ListenAndServe()
+		}
 	}()
 	logger("Server running...", "press ctrl-c to stop.")
 	if open {
-		if err := browser.OpenURL("http://" + address); err != nil {
+		if err := browser.OpenURL(siteURL); err != nil {
 			fmt.Println("Error opening page:", err)
 		}
 	}
 	return <-c
 }
 
+// The site is mounted under its baseurl, as on GitHub Pages. A request for
+// the server root redirects there.
+//
+// The handler resolves and renders the request while it holds the read
+// lock, and writes the response after releasing it.
+//
+// Requests that match a serve.proxy prefix are forwarded, whatever their
+// method.
 func (s *Server) handler(rw http.ResponseWriter, r *http.Request) {
-	s.Lock()
-	defer s.Unlock()
+	s.RLock()
+	proxy := s.proxyHandler(r.URL.Path)
+	s.RUnlock()
+	if proxy != nil {
+		proxy.ServeHTTP(rw, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	default:
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.RLock()
+	h := s.route(r)
+	s.RUnlock()
+	h(rw, r)
+}
 
+// route returns the handler for a request.
+func (s *Server) route(r *http.Request) http.HandlerFunc {
 	var (
-		site     = s.Site
-		urlpath  = r.URL.Path
-		p, found = site.URLPage(urlpath)
+		site    = s.Site
+		baseurl = strings.TrimSuffix(site.Config().BaseURL, "/")
+		urlpath = r.URL.Path
 	)
-	if !found {
-		rw.WriteHeader(http.StatusNotFound)
-		p, found = site.Routes["/404.html"]
+	switch {
+	case urlpath == liveReloadScriptPath && site.Config().Watch:
+		return liveReloadScriptHandler
+	case urlpath+"/" == dashboardPath:
+		return redirectHandler(dashboardPath, http.StatusFound)
+	case strings.HasPrefix(urlpath, dashboardPath):
+		return s.dashboardHandler(r)
+	case urlpath == liveReloadSocketPath && s.lrProxy != nil:
+		if _, found := site.URLPage(urlpath); baseurl != "" || !found {
+			return s.lrProxy.ServeHTTP
+		}
 	}
-	if !found {
-		fmt.Fprintf(rw, "404 page not found: %s\n", urlpath) // nolint: gas
-		return
+	switch {
+	case baseurl == "":
+	case urlpath == "/" || urlpath == baseurl:
+		return redirectHandler(baseurl+"/", http.StatusFound)
+	case strings.HasPrefix(urlpath, baseurl+"/"):
+		urlpath = strings.TrimPrefix(urlpath, baseurl)
+	default:
+		return s.notFound(urlpath)
 	}
-	mimeType := mime.TypeByExtension(p.OutputExt())
-	if mimeType != "" {
-		rw.Header().Set("Content-Type", mimeType)
+	headers := http.Header{}
+	s.setSiteHeaders(headers, urlpath)
+	h := s.siteHandler(urlpath)
+	return func(rw http.ResponseWriter, r *http.Request) {
+		for k, v := range headers {
+			rw.Header()[k] = v
+		}
+		h(rw, r)
 	}
-	var w io.Writer = rw
-	if strings.HasPrefix(mimeType, "text/html;") {
-		w = NewLiveReloadInjector(w)
+}
+
+// siteHandler returns the handler for a site-relative URL path.
+func (s *Server) siteHandler(urlpath string) http.HandlerFunc {
+	p, found := s.Site.URLPage(urlpath)
+	if !found {
+		return s.notFound(urlpath)
 	}
-	err := site.WriteDocument(w, p)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error rendering %s: %s\n", urlpath, err)
-		eng := liquid.NewEngine()
-		excerpt, path := fileErrorContext(err)
-		out, e := eng.ParseAndRenderString(renderErrorTemplate, liquid.Bindings{
-			"error":   fmt.Sprint(err),
-			"excerpt": excerpt,
-			"path":    path,
-			"watch":   site.Config().Watch,
-		})
-		if e != nil {
-			panic(e)
-		}
-		if _, err := io.WriteString(w, out); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+	if url, ok := redirectURL(p); ok {
+		return redirectHandler(url, http.StatusMovedPermanently)
+	}
+	return s.documentHandler(p, http.StatusOK)
+}
+
+// redirectURL returns the target of a jekyll-redirect-from redirect, and
+// true; or false if the document isn't a redirect. A redirect_from page is a
+// pages.Redirect. A page with redirect_to front matter has a redirect.to
+// variable.
+func redirectURL(d pages.Document) (string, bool) {
+	switch d := d.(type) {
+	case pages.Redirect:
+		return d.RedirectURL(), true
+	case pages.Page:
+		if r, ok := d.FrontMatter()["redirect"].(map[string]interface{}); ok {
+			if to, ok := r["to"].(string); ok && to != "" {
+				return to, true
+			}
 		}
 	}
+	return "", false
+}
+
+func redirectHandler(url string, code int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		http.Redirect(rw, r, url, code)
+	}
+}
+
+func (s *Server) notFound(urlpath string) http.HandlerFunc {
+	if p, found := s.Site.Routes["/404.html"]; found {
+		return s.documentHandler(p, http.StatusNotFound)
+	}
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(rw, "404 page not found: %s\n", urlpath) // nolint: gas
+	}
+}
+
+func (s *Server) writeRenderError(w io.Writer, err error) {
+	eng := liquid.NewEngine()
+	excerpt, path := fileErrorContext(err)
+	out, e := eng.ParseAndRenderString(renderErrorTemplate, liquid.Bindings{
+		"error":   fmt.Sprint(err),
+		"excerpt": excerpt,
+		"path":    path,
+		"watch":   s.Site.Config().Watch,
+	})
+	if e != nil {
+		panic(e)
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HTTP response: %s", err)
+	}
 }
 
 func fileErrorContext(e error) (s, path string) {
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jaschaephraim/lrserver"
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/site"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *Server {
+	s, err := site.FromDirectory("testdata/site", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	return &Server{Site: s}
+}
+
+func serveRequest(s *Server, method, urlpath string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, urlpath, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	s.handler(w, r)
+	return w
+}
+
+func TestServer_baseurl(t *testing.T) {
+	s := newTestServer(t)
+
+	w := serveRequest(s, "GET", "/", nil)
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, "/docs/", w.Header().Get("Location"))
+
+	w = serveRequest(s, "GET", "/docs", nil)
+	require.Equal(t, http.StatusFound, w.Code)
+	require.Equal(t, "/docs/", w.Header().Get("Location"))
+
+	w = serveRequest(s, "GET", "/docs/", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "home")
+
+	w = serveRequest(s, "GET", "/docs/about.html", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "about")
+
+	w = serveRequest(s, "GET", "/about.html", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), "not here")
+
+	w = serveRequest(s, "POST", "/docs/", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestServer_redirect(t *testing.T) {
+	s := newTestServer(t)
+	w := serveRequest(s, "GET", "/docs/old.html", nil)
+	require.Equal(t, http.StatusMovedPermanently, w.Code)
+	require.Equal(t, "/docs/about.html", w.Header().Get("Location"))
+
+	w = serveRequest(s, "GET", "/docs/moved.html", nil)
+	require.Equal(t, http.StatusMovedPermanently, w.Code)
+	require.Equal(t, "https://example.com/moved/", w.Header().Get("Location"))
+}
+
+func TestServer_conditional(t *testing.T) {
+	s := newTestServer(t)
+	for _, urlpath := range []string{"/docs/about.html", "/docs/assets/style.css"} {
+		w := serveRequest(s, "GET", urlpath, nil)
+		require.Equal(t, http.StatusOK, w.Code, urlpath)
+		etag := w.Header().Get("ETag")
+		require.NotEmpty(t, etag, urlpath)
+		body := w.Body.String()
+
+		w = serveRequest(s, "HEAD", urlpath, nil)
+		require.Equal(t, http.StatusOK, w.Code, urlpath)
+		require.Equal(t, etag, w.Header().Get("ETag"), urlpath)
+		require.Equal(t, len(body), int(w.Result().ContentLength), urlpath)
+		require.Empty(t, w.Body.String(), urlpath)
+
+		w = serveRequest(s, "GET", urlpath, http.Header{"If-None-Match": {`"other", ` + etag}})
+		require.Equal(t, http.StatusNotModified, w.Code, urlpath)
+		require.Empty(t, w.Body.String(), urlpath)
+
+		w = serveRequest(s, "GET", urlpath, http.Header{"If-None-Match": {`"other"`}})
+		require.Equal(t, http.StatusOK, w.Code, urlpath)
+	}
+}
+
+func TestServer_cache(t *testing.T) {
+	s := newTestServer(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, urlpath := range []string{"/docs/", "/docs/about.html", "/docs/assets/style.css", "/docs/missing"} {
+				serveRequest(s, "GET", urlpath, nil)
+			}
+		}()
+	}
+	wg.Wait()
+	_, found := s.cache.get("/about.html")
+	require.True(t, found)
+	_, found = s.cache.get("/assets/style.css")
+	require.False(t, found)
+
+	s.cache.clear()
+	_, found = s.cache.get("/about.html")
+	require.False(t, found)
+}
+
+func TestServer_liveReload(t *testing.T) {
+	s := newTestServer(t)
+	s.Site.Config().Watch = true
+	w := serveRequest(s, "GET", "/docs/", nil)
+	require.Contains(t, w.Body.String(), `<script src="/__gojekyll/livereload.js?port=35729"></script></head>`)
+
+	s = newTestServer(t)
+	s.Site.Config().Watch = true
+	s.Site.Config().LiveReloadHost = "example.local"
+	s.Site.Config().LiveReloadPort = 35730
+	w = serveRequest(s, "GET", "/docs/", nil)
+	require.Contains(t, w.Body.String(), `<script src="/__gojekyll/livereload.js?host=example.local&amp;port=35730"></script>`)
+
+	w = serveRequest(s, "GET", "/docs/assets/style.css", nil)
+	require.NotContains(t, w.Body.String(), "livereload")
+
+	w = serveRequest(s, "GET", "/__gojekyll/livereload.js", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, lrserver.JS, w.Body.String())
+
+	// The Live Reload script doesn't hide a site file.
+	s.Site.Config().BaseURL = ""
+	w = serveRequest(s, "GET", "/livereload.js", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "the site's own livereload.js")
+}
+
+func TestIsStylesheetChange(t *testing.T) {
+	require.True(t, isStylesheetChange([]string{"assets/css/main.scss", "_sass/_base.sass", "style.CSS"}))
+	require.False(t, isStylesheetChange([]string{"assets/css/main.scss", "index.md"}))
+	require.False(t, isStylesheetChange([]string{"_config.yml"}))
+	require.False(t, isStylesheetChange(nil))
+}
+
+func TestServer_liveReloadHTTPS(t *testing.T) {
+	s := newTestServer(t)
+	s.Site.Config().Watch = true
+	s.https = true
+	w := serveRequest(s, "GET", "/docs/", nil)
+	require.Contains(t, w.Body.String(), `<script src="/__gojekyll/livereload.js?port="></script>`)
+
+	s.lrProxy = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusSwitchingProtocols)
+	})
+	w = serveRequest(s, "GET", "/livereload", nil)
+	require.Equal(t, http.StatusSwitchingProtocols, w.Code)
+}
@@ -0,0 +1 @@
+// the site's own livereload.js
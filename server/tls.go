@@ -0,0 +1,110 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/osteele/gojekyll/cache"
+	"github.com/osteele/gojekyll/utils"
+)
+
+// tlsConfig returns the TLS configuration for the ssl_cert and ssl_key
+// options, or for a self-signed certificate; or nil, to serve HTTP.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	var (
+		cfg  = s.Site.Config()
+		cert tls.Certificate
+		err  error
+	)
+	switch {
+	case cfg.SSLCert != "" || cfg.SSLKey != "":
+		if cfg.SSLCert == "" || cfg.SSLKey == "" {
+			return nil, fmt.Errorf("--ssl-cert and --ssl-key must be specified together")
+		}
+		certFile := filepath.Join(cfg.SourceDir(), cfg.SSLCert)
+		keyFile := filepath.Join(cfg.SourceDir(), cfg.SSLKey)
+		cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+		err = utils.WrapError(err, "loading the SSL certificate")
+	case cfg.SSLSelfSigned:
+		cert, err = selfSignedCertificate(cfg.Host)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
+
+// selfSignedCertificate returns a certificate for the host, and for
+// localhost. It's kept in the file cache, and replaced each year so that it
+// doesn't expire while it's in use.
+func selfSignedCertificate(host string) (tls.Certificate, error) {
+	year := time.Now().Format("2006")
+	b, err := cache.WithFile("self-signed certificate "+year, host, func() (string, error) {
+		return generateCertificate(host, time.Now())
+	})
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	// The cached file holds both the certificate and key blocks.
+	return tls.X509KeyPair([]byte(b), []byte(b))
+}
+
+// generateCertificate returns the PEM encoding of a new certificate and its
+// private key, valid for two years from now.
+func generateCertificate(host string, now time.Time) (string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", err
+	}
+	tmpl := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"gojekyll development server"}, CommonName: host},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(2, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !ip.IsLoopback() {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		}
+	} else if host != "" && host != "localhost" {
+		tmpl.DNSNames = append(tmpl.DNSNames, host)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		return "", err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	if err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		return "", err
+	}
+	if err := pem.Encode(buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCertificate(t *testing.T) {
+	b, err := generateCertificate("dev.example.com", time.Now())
+	require.NoError(t, err)
+	pair, err := tls.X509KeyPair([]byte(b), []byte(b))
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	require.NoError(t, cert.VerifyHostname("dev.example.com"))
+	require.NoError(t, cert.VerifyHostname("localhost"))
+	require.NoError(t, cert.VerifyHostname("127.0.0.1"))
+	require.Error(t, cert.VerifyHostname("example.com"))
+
+	b, err = generateCertificate("192.168.1.2", time.Now())
+	require.NoError(t, err)
+	pair, err = tls.X509KeyPair([]byte(b), []byte(b))
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(pair.Certificate[0])
+	require.NoError(t, err)
+	require.Contains(t, cert.IPAddresses, net.ParseIP("192.168.1.2").To4())
+}
+
+func TestServer_tlsConfig(t *testing.T) {
+	s := newTestServer(t)
+	tc, err := s.tlsConfig()
+	require.NoError(t, err)
+	require.Nil(t, tc)
+
+	s.Site.Config().SSLCert = "cert.pem"
+	_, err = s.tlsConfig()
+	require.Error(t, err)
+
+	s.Site.Config().SSLCert = ""
+	s.Site.Config().SSLSelfSigned = true
+	tc, err = s.tlsConfig()
+	require.NoError(t, err)
+	require.Len(t, tc.Certificates, 1)
+}
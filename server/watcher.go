@@ -3,62 +3,123 @@ package server
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/osteele/gojekyll/site"
 )
 
-// Create a goroutine that rebuilds the site when files change.
+// Create a goroutine that rebuilds the site when files change. After a
+// reload reads a new site, it watches that site's files instead, so that it
+// picks up changes to include, exclude, etc.
 func (s *Server) watchReload() error {
-	site := s.Site
-	// FIXME reload swaps in a new site but we're still watching the old one.
-	// This won't pick up changes to include, exclude, etc.
-	changes, err := site.WatchFiles()
+	var (
+		watched      = s.Site
+		done         = make(chan struct{})
+		changes, err = watched.WatchFilesUntil(done)
+	)
 	if err != nil {
 		return err
 	}
 	go func() {
-		for change := range changes {
-			// Resolves filenames to URLS *before* reloading the site, in case the latter
-			// changes the url -> filename routes.
-			urls := map[string]bool{}
-			for _, rel := range change.Paths {
-				url, ok := site.FilenameURLPath(rel)
-				if ok {
-					urls[url] = true
-				}
+		for {
+			// not a range loop, since changes changes when the site does
+			change, ok := <-changes
+			if !ok {
+				return
 			}
-			if site.RequiresFullReload(change.Paths) {
-				for u := range site.Routes {
-					urls[u] = true
-				}
+			// A change that doesn't require a full reload leaves the
+			// configuration as it was, so the new site's files are the ones
+			// that are already watched.
+			full := watched.RequiresFullReload(change.Paths)
+			s.processChange(change)
+			// This goroutine is the only one that replaces s.Site.
+			if !full || s.Site == watched {
+				continue
 			}
-			// reload the site
-			s.reload(change)
-			// tell the pages their files (may have) changed
-			for url := range urls {
-				s.lr.Reload(url)
+			close(done)
+			watched, done = s.Site, make(chan struct{})
+			if changes, err = watched.WatchFilesUntil(done); err != nil {
+				fmt.Fprintln(os.Stderr, "Error watching files:", err)
+				return
 			}
 		}
 	}()
 	return nil
 }
 
-func (s *Server) reload(change site.FilesEvent) {
-	s.Lock()
-	defer s.Unlock()
+// processChange reloads the site, and tells the browser to reload the pages
+// that the change affects.
+func (s *Server) processChange(change site.FilesEvent) {
+	site := s.Site
+	// Resolves filenames to URLS *before* reloading the site, in case the latter
+	// changes the url -> filename routes.
+	urls := map[string]bool{}
+	for _, rel := range change.Paths {
+		url, ok := site.FilenameURLPath(rel)
+		if ok {
+			urls[url] = true
+		}
+	}
+	switch {
+	case isStylesheetChange(change.Paths):
+		// A Sass partial can affect any stylesheet.
+		for u, d := range site.Routes {
+			if d.OutputExt() == ".css" {
+				urls[u] = true
+			}
+		}
+	case site.RequiresFullReload(change.Paths):
+		for u := range site.Routes {
+			urls[u] = true
+		}
+	}
+	// reload the site
+	s.reload(change)
+	// tell the pages their files (may have) changed
+	s.reloadURLs(urls)
+}
 
+func (s *Server) reload(change site.FilesEvent) {
 	// similar code to site.WatchRebuild
 	fmt.Printf("Re-reading: %v...", change)
 	start := time.Now()
-	site, err := s.Site.Reloaded(change.Paths)
+	site, err := s.reloadedSite()
+	var (
+		proxies     []proxyRoute
+		headerRules []headerRule
+	)
+	if err == nil {
+		proxies, err = newProxyRoutes(site.Config())
+	}
+	if err == nil {
+		headerRules, err = readHeadersFile(filepath.Join(site.SourceDir(), headersFileName))
+	}
 	if err != nil {
 		fmt.Println()
 		fmt.Fprintln(os.Stderr, err.Error())
+		s.log.failedLoad(err)
 		s.lr.Alert(fmt.Sprintf("Error reading site configuration: %s", err))
 		return
 	}
+	s.Lock()
 	s.Site = site
 	s.Site.SetAbsoluteURL("")
+	s.cache.clear()
+	generation := s.log.reset()
+	s.proxies = proxies
+	s.headers = headerRules
+	s.Unlock()
+	go s.checkSite(site, generation)
 	fmt.Printf("done (%.2fs)\n", time.Since(start).Seconds())
 }
+
+// reloadedSite reads a new site after a change, so that requests continue to
+// be served from the current one in the meantime. It doesn't hold the lock;
+// reload takes it only to swap in the new site.
+func (s *Server) reloadedSite() (*site.Site, error) {
+	s.RLock()
+	site := s.Site
+	s.RUnlock()
+	return site.Reread()
+}
@@ -0,0 +1,213 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/pages"
+	"golang.org/x/net/html"
+)
+
+// A BrokenLink is a reference, in a page's output, to a URL or fragment that
+// isn't in the site.
+type BrokenLink struct {
+	File   string // output file, relative to the destination directory
+	Source string // source file, relative to the source directory; or "" for a generated page
+	Line   int    // line number in the output file
+	Attr   string // href or src
+	Ref    string // the attribute value
+	Reason string
+}
+
+func (l BrokenLink) Error() string {
+	msg := fmt.Sprintf("%s:%d: %s=%q: %s", l.File, l.Line, l.Attr, l.Ref, l.Reason)
+	if l.Source != "" {
+		msg += fmt.Sprintf(" (from %s)", l.Source)
+	}
+	return msg
+}
+
+// A linkReference is an href or src attribute in a page's output.
+type linkReference struct {
+	attr, value string
+	line        int
+}
+
+// A checkedPage is the output of a page, reduced to what CheckLinks needs.
+type checkedPage struct {
+	doc  pages.Document
+	ids  map[string]bool // element ids and anchor names
+	refs []linkReference
+}
+
+// CheckLinks renders the site's HTML pages in memory, and returns the
+// references in them to URLs that aren't in Routes, to fragments that aren't
+// an element id in the target page, and to root-relative URLs that don't
+// begin with the site's baseurl.
+//
+// References to other sites aren't checked, except for those that begin with
+// the site's url.
+func (s *Site) CheckLinks() ([]BrokenLink, error) {
+	var (
+		urls    = make([]string, 0, len(s.Routes))
+		checked = map[pages.Document]*checkedPage{}
+		broken  []BrokenLink
+	)
+	for u := range s.Routes {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	for _, u := range urls {
+		d := s.Routes[u]
+		if d.IsStatic() || !isHTMLURL(u) {
+			continue
+		}
+		buf := new(bytes.Buffer)
+		if err := s.WriteDocument(buf, d); err != nil {
+			return nil, err
+		}
+		checked[d] = scanHTMLLinks(d, buf.Bytes())
+	}
+	for _, u := range urls {
+		p, ok := checked[s.Routes[u]]
+		if !ok {
+			continue
+		}
+		for _, ref := range p.refs {
+			if reason := s.checkLink(u, ref.value, checked); reason != "" {
+				broken = append(broken, BrokenLink{
+					File:   s.outputPath(u),
+					Source: s.sourcePath(p.doc),
+					Line:   ref.line,
+					Attr:   ref.attr,
+					Ref:    ref.value,
+					Reason: reason,
+				})
+			}
+		}
+	}
+	return broken, nil
+}
+
+// checkLink returns the reason that a reference from the page at pageURL is
+// broken, or "" if it isn't.
+func (s *Site) checkLink(pageURL, ref string, checked map[pages.Document]*checkedPage) string {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "malformed URL"
+	}
+	var (
+		baseURL = strings.TrimSuffix(s.cfg.BaseURL, "/")
+		target  = u.Path
+	)
+	if site, err := url.Parse(s.cfg.AbsoluteURL); err == nil && site.Host != "" && u.Host == site.Host {
+		u.Scheme, u.Host = "", ""
+	}
+	switch {
+	case u.Scheme != "" || u.Host != "" || u.Opaque != "":
+		// another site, or a mailto:, tel:, javascript:, or data: URL
+		return ""
+	case target == "" && u.RawQuery == "" && u.Fragment == "":
+		return ""
+	case target == "":
+		target = pageURL
+	case strings.HasPrefix(target, "/"):
+		if baseURL != "" {
+			if target != baseURL && !strings.HasPrefix(target, baseURL+"/") {
+				return fmt.Sprintf("missing baseurl %s", baseURL)
+			}
+			target = strings.TrimPrefix(target, baseURL)
+		}
+	default:
+		dir := pageURL[:strings.LastIndex(pageURL, "/")+1]
+		target = path.Join(dir, target)
+		if strings.HasSuffix(u.Path, "/") && !strings.HasSuffix(target, "/") {
+			target += "/"
+		}
+	}
+	d, found := s.lookupLinkTarget(target)
+	if !found {
+		return "not found"
+	}
+	if u.Fragment == "" || u.Fragment == "top" {
+		return ""
+	}
+	if p, ok := checked[d]; ok && !p.ids[u.Fragment] {
+		return fmt.Sprintf("%s has no element with id %q", d.URL(), u.Fragment)
+	}
+	return ""
+}
+
+// lookupLinkTarget returns the document that a server would serve for a URL
+// path: the document at that path, or its index.html, or the .html file
+// for an extensionless path.
+func (s *Site) lookupLinkTarget(urlpath string) (pages.Document, bool) {
+	if urlpath == "" {
+		urlpath = "/"
+	}
+	candidates := []string{urlpath, path.Join(urlpath, "index.html"), path.Join(urlpath, "index.htm")}
+	if !strings.HasSuffix(urlpath, "/") {
+		candidates = append(candidates, urlpath+"/", urlpath+".html")
+	}
+	for _, c := range candidates {
+		if d, found := s.Routes[c]; found {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// outputPath returns the destination-relative path of the output file for a URL.
+func (s *Site) outputPath(u string) string {
+	rel := strings.TrimPrefix(u, "/")
+	if rel == "" || strings.HasSuffix(rel, "/") {
+		rel += "index.html"
+	}
+	return rel
+}
+
+func (s *Site) sourcePath(d pages.Document) string {
+	if d.Source() == "" {
+		return ""
+	}
+	return s.RelativePath(d.Source())
+}
+
+func isHTMLURL(u string) bool {
+	ext := path.Ext(u)
+	return ext == ".html" || ext == ".htm" || strings.HasSuffix(u, "/")
+}
+
+// scanHTMLLinks collects the element ids and link references in a document.
+func scanHTMLLinks(d pages.Document, doc []byte) *checkedPage {
+	var (
+		z    = html.NewTokenizer(bytes.NewReader(doc))
+		p    = &checkedPage{doc: d, ids: map[string]bool{}}
+		line = 1
+	)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return p
+		}
+		raw := z.Raw()
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			tn, hasAttr := z.TagName()
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				switch k := string(key); {
+				case k == "id", k == "name" && string(tn) == "a":
+					p.ids[string(val)] = true
+				case k == "href", k == "src":
+					p.refs = append(p.refs, linkReference{k, string(val), line})
+				}
+			}
+		}
+		line += bytes.Count(raw, []byte("\n"))
+	}
+}
@@ -0,0 +1,27 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite_CheckLinks(t *testing.T) {
+	s, err := FromDirectory("testdata/check", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	broken, err := s.CheckLinks()
+	require.NoError(t, err)
+
+	var messages []string
+	for _, l := range broken {
+		messages = append(messages, l.Error())
+	}
+	require.Equal(t, []string{
+		`about/index.html:5: href="/about/": missing baseurl /blog (from about.html)`,
+		`about/index.html:6: href="/blog/missing/": not found (from about.html)`,
+		`about/index.html:7: src="../assets/logo.png": not found (from about.html)`,
+		`about/index.html:8: href="/blog/#history": / has no element with id "history" (from about.html)`,
+	}, messages)
+}
@@ -0,0 +1,112 @@
+package site
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/frontmatter"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/osteele/gojekyll/utils"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A dataPageSource is the generator and record that a data page is made from.
+type dataPageSource struct {
+	generator config.DataPages
+	record    interface{}
+}
+
+// generateDataPages adds a page for each record of the data files that
+// the data_pages configuration names.
+func (s *Site) generateDataPages() error {
+	s.dataPages = map[pages.Page]dataPageSource{}
+	for _, g := range s.cfg.DataPages {
+		records, err := s.dataRecords(g)
+		if err != nil {
+			return utils.WrapError(err, "data_pages")
+		}
+		for i, record := range records {
+			p, err := s.newDataPage(g, record, "")
+			if err != nil {
+				return utils.WrapError(err, fmt.Sprintf("data_pages: record %d of %s", i+1, g.Data))
+			}
+			s.dataPages[p] = dataPageSource{g, record}
+			s.AddDocument(p, true)
+			s.nonCollectionPages = append(s.nonCollectionPages, p)
+		}
+	}
+	return nil
+}
+
+// dataRecords returns the list of records at the generator's data path.
+func (s *Site) dataRecords(g config.DataPages) ([]interface{}, error) {
+	var data interface{} = s.data
+	for _, key := range g.DataPath() {
+		value, found := dataField(data, key)
+		if !found {
+			return nil, fmt.Errorf("site.data.%s not found", g.Data)
+		}
+		data = value
+	}
+	records, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("site.data.%s is not a list", g.Data)
+	}
+	return records, nil
+}
+
+// newDataPage creates the page for a data record. If lang isn't empty, the
+// page is in that language.
+func (s *Site) newDataPage(g config.DataPages, record interface{}, lang string) (pages.Page, error) {
+	value, found := dataField(record, g.SlugField())
+	if !found || value == nil {
+		return nil, fmt.Errorf("missing %q field", g.SlugField())
+	}
+	slug := strings.Trim(utils.Slugify(fmt.Sprint(value)), "-")
+	if slug == "" {
+		return nil, fmt.Errorf("empty %q field", g.SlugField())
+	}
+	var (
+		rel = path.Join(strings.Join(g.DataPath(), "/"), slug+".html")
+		fm  = frontmatter.FrontMatter{
+			g.VariableName(): record,
+			"slug":           slug,
+		}
+	)
+	if title, found := dataField(record, "title"); found {
+		fm["title"] = title
+	}
+	if g.Layout != "" {
+		fm["layout"] = g.Layout
+	}
+	if g.Permalink != "" {
+		fm["permalink"] = g.Permalink
+	}
+	if lang != "" {
+		fm["lang"] = lang
+	}
+	defaults := frontmatter.FrontMatter(s.cfg.GetFrontMatterDefaults(config.PagesScopeType, rel))
+	return pages.NewGeneratedPage(s, rel, defaults.Merged(fm))
+}
+
+// dataField returns the value of a field of a data file map, as read from
+// YAML or JSON.
+func dataField(data interface{}, key string) (interface{}, bool) {
+	switch m := data.(type) {
+	case map[string]interface{}:
+		value, found := m[key]
+		return value, found
+	case map[interface{}]interface{}:
+		value, found := m[key]
+		return value, found
+	case yaml.MapSlice:
+		for _, item := range m {
+			if item.Key == key {
+				return item.Value, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,52 @@
+package site
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite_generateDataPages(t *testing.T) {
+	s, err := FromDirectory("testdata/data_pages", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+
+	render := func(url string) string {
+		require.Contains(t, s.Routes, url)
+		buf := new(bytes.Buffer)
+		require.NoError(t, s.WriteDocument(buf, s.Routes[url]))
+		return buf.String()
+	}
+	require.Equal(t, "Widget: 10\n", render("/products/w-100/"))
+	require.Equal(t, "Gadget: 25\n", render("/products/g-200/"))
+	require.Contains(t, render("/index.html"), "/products/w-100/")
+}
+
+func TestSite_generateDataPages_languages(t *testing.T) {
+	s, err := FromDirectory("testdata/data_pages", config.Flags{})
+	require.NoError(t, err)
+	s.cfg.Languages = []string{"en", "fr"}
+	require.NoError(t, s.Read())
+	require.Contains(t, s.Routes, "/products/w-100/")
+	require.Contains(t, s.Routes, "/fr/products/w-100/")
+	p := s.Routes["/fr/products/w-100/"].(pages.Page)
+	require.Equal(t, "fr", p.Lang())
+	translations := p.FrontMatter()["translations"].([]pages.Page)
+	require.Len(t, translations, 1)
+	require.Equal(t, "/products/w-100/", translations[0].URL())
+}
+
+func TestSite_generateDataPages_errors(t *testing.T) {
+	s, err := FromDirectory("testdata/data_pages", config.Flags{})
+	require.NoError(t, err)
+	s.cfg.DataPages[0].Slug = "id"
+	require.Error(t, s.Read())
+
+	s, err = FromDirectory("testdata/data_pages", config.Flags{})
+	require.NoError(t, err)
+	s.cfg.DataPages[0].Data = "missing"
+	require.Error(t, s.Read())
+}
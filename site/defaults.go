@@ -0,0 +1,32 @@
+package site
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/pages"
+)
+
+// FrontMatterDefaultsScope returns the front matter defaults scope type, and
+// the site-relative path, that select the defaults for a document.
+func (s *Site) FrontMatterDefaultsScope(d pages.Document) (typename, rel string) {
+	rel = filepath.ToSlash(s.RelativePath(d.Source()))
+	p, ok := d.(pages.Page)
+	if !ok {
+		for _, c := range s.Collections {
+			if strings.HasPrefix(rel, filepath.ToSlash(c.PathPrefix())) {
+				return c.Name, rel
+			}
+		}
+		return "", rel
+	}
+	switch name := p.FrontMatter().String("collection", ""); {
+	case name == "":
+		return config.PagesScopeType, rel
+	case name == "posts" && strings.HasPrefix(rel, "_drafts/"):
+		return config.DraftsScopeType, rel
+	default:
+		return name, rel
+	}
+}
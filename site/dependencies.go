@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/osteele/gojekyll/config"
 	"github.com/osteele/gojekyll/pages"
 	"github.com/osteele/gojekyll/utils"
 )
@@ -39,19 +40,23 @@ func (s *Site) Exclude(siteRel string) bool {
 // static asset can cause pages to change if they reference its
 // variables.
 //
-// This function works on relative paths. It does not work for theme
-// sources.
+// This function works on relative paths. A theme source always requires a
+// full reload.
 func (s *Site) RequiresFullReload(paths []string) bool {
 	for _, path := range paths {
 		switch {
 		case s.cfg.IsConfigPath(path):
 			return true
-		case s.Exclude(path):
+		case s.isThemePath(path) || isOutsideSource(path):
+			return true
+		case s.Exclude(path) && !isPostPath(path):
 			continue
 		case !s.cfg.Incremental:
 			return true
 		case strings.HasPrefix(path, s.cfg.DataDir):
 			return true
+		case strings.HasPrefix(path, config.I18nDir):
+			return true
 		case strings.HasPrefix(path, s.cfg.IncludesDir):
 			return true
 		case strings.HasPrefix(path, s.cfg.LayoutsDir):
@@ -63,6 +68,31 @@ func (s *Site) RequiresFullReload(paths []string) bool {
 	return false
 }
 
+// isPostPath returns true if the site-relative path is inside a _posts or
+// _drafts directory. Exclude excludes these if they're below the top level,
+// but they're read into the posts collection.
+func isPostPath(rel string) bool {
+	_, ok := pages.PostDirectoryCategories(filepath.ToSlash(rel))
+	return ok
+}
+
+// isOutsideSource returns true if a relative path is outside the directory
+// that it's relative to.
+func isOutsideSource(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isThemePath returns true if a path relative to the site source is in the
+// theme directory. The theme directory can be outside the source directory,
+// or inside it; for example, in an excluded vendor/bundle directory.
+func (s *Site) isThemePath(rel string) bool {
+	if s.themeDir == "" {
+		return false
+	}
+	r, err := filepath.Rel(s.themeDir, filepath.Join(utils.MustAbs(s.SourceDir()), rel))
+	return err == nil && !isOutsideSource(r)
+}
+
 // De-dup relative paths, and filter to those that might affect the build.
 //
 // Site watch uses this to decide when to send events.
@@ -87,9 +117,13 @@ loop:
 	return result
 }
 
-// Returns true if the file or a parent directory is excluded.
+// Returns true if the file or a parent directory is excluded. The exclude
+// settings don't apply to theme files.
 // Cf. Site.Exclude.
 func (s *Site) fileAffectsBuild(rel string) bool {
+	if s.isThemePath(rel) {
+		return !strings.HasPrefix(filepath.Base(rel), ".")
+	}
 	for rel != "" {
 		switch {
 		case rel == ".":
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -34,8 +35,23 @@ func TestSite_RequiresFullReload(t *testing.T) {
 	require.False(t, s.RequiresFullReload([]string{}))
 	require.False(t, s.RequiresFullReload([]string{"file.md"}))
 	require.True(t, s.RequiresFullReload([]string{"_config.yml"}))
+	require.True(t, s.RequiresFullReload([]string{"../theme/_layouts/default.html"}))
 }
 
 //func TestSite_affectsBuildFilter(t *testing.T) {
-//func TestSite_fileAffectsBuild(t *testing.T) {
+
+func TestSite_fileAffectsBuild(t *testing.T) {
+	s := New(config.Flags{})
+	s.cfg.Source = "testdata/site1"
+	s.cfg.Exclude = []string{"vendor"}
+	s.themeDir = utils.MustAbs("testdata/site1/vendor/bundle/theme")
+	require.True(t, s.fileAffectsBuild("index.md"))
+	require.False(t, s.fileAffectsBuild("vendor/lib.rb"))
+	require.True(t, s.fileAffectsBuild("vendor/bundle/theme/_layouts/default.html"))
+	require.False(t, s.fileAffectsBuild("vendor/bundle/theme/.git"))
+	require.True(t, s.RequiresFullReload([]string{"vendor/bundle/theme/_layouts/default.html"}))
+	require.True(t, s.watchesDir(utils.MustAbs("testdata/site1/vendor/bundle/theme/_layouts")))
+	require.False(t, s.watchesDir(utils.MustAbs("testdata/site1/vendor")))
+}
+
 //func TestSite_invalidatesDoc(t *testing.T) {
@@ -0,0 +1,110 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/plugins"
+	"github.com/osteele/gojekyll/utils"
+	"github.com/osteele/liquid/evaluator"
+)
+
+// Diagnose returns descriptions of problems with the site's configuration
+// and content, for the doctor command: route collisions, configuration keys
+// that are deprecated, unsupported, or misspelled, plugins that gojekyll
+// doesn't emulate, posts whose dates can't be parsed, and output paths that
+// differ only by case.
+func (s *Site) Diagnose() ([]string, error) {
+	var problems []string
+	for _, c := range s.RouteCollisions() {
+		problems = append(problems, c.Error())
+	}
+	for _, w := range s.cfg.KeyWarnings() {
+		problems = append(problems, fmt.Sprintf("%s: %s", s.configFileName(), w))
+	}
+	for _, name := range s.cfg.Plugins {
+		if _, found := plugins.Lookup(name); !found {
+			problems = append(problems, fmt.Sprintf("plugin %s is not emulated by gojekyll", name))
+		}
+	}
+	dates, err := s.postDateProblems()
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, dates...)
+	problems = append(problems, s.caseCollisions()...)
+	return problems, nil
+}
+
+func (s *Site) configFileName() string {
+	if s.cfg.ConfigFile == "" {
+		return "_config.yml"
+	}
+	return filepath.Base(s.cfg.ConfigFile)
+}
+
+// postDateProblems reports the files in the _posts directories that the posts
+// collection reads whose names don't begin with a date, which are therefore
+// skipped; and the posts whose date front matter variable isn't a date.
+func (s *Site) postDateProblems() ([]string, error) {
+	var problems []string
+	for _, p := range s.Posts() {
+		if value, ok := p.FrontMatter()["date"].(string); ok {
+			if _, err := evaluator.ParseDate(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: can't parse date %q", s.RelativePath(p.Source()), value))
+			}
+		}
+	}
+	for _, c := range s.Collections {
+		if !c.IsPostsCollection() {
+			continue
+		}
+		dirs, err := c.PostDirectories()
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			if filepath.Base(dir) != "_posts" {
+				// drafts don't need dates
+				continue
+			}
+			if err := filepath.Walk(filepath.Join(s.SourceDir(), dir), func(filename string, info os.FileInfo, err error) error {
+				switch {
+				case err != nil:
+					return err
+				case info.IsDir(), strings.HasPrefix(info.Name(), "."):
+					return nil
+				}
+				if _, _, found := utils.ParseFilenameDateTitle(info.Name()); !found {
+					rel := filepath.ToSlash(utils.MustRel(s.SourceDir(), filename))
+					problems = append(problems, fmt.Sprintf("%s: skipped, because its filename doesn't begin with a YYYY-MM-DD date", rel))
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return problems, nil
+}
+
+// caseCollisions reports output paths that differ only by case. On a
+// case-insensitive file system, one of these overwrites the other.
+func (s *Site) caseCollisions() (problems []string) {
+	groups := map[string][]string{}
+	for u := range s.Routes {
+		p := s.outputPath(u)
+		groups[strings.ToLower(p)] = append(groups[strings.ToLower(p)], p)
+	}
+	for _, paths := range groups {
+		if len(paths) > 1 {
+			sort.Strings(paths)
+			problems = append(problems, fmt.Sprintf("output paths differ only by case: %s", strings.Join(paths, ", ")))
+		}
+	}
+	sort.Strings(problems)
+	return
+}
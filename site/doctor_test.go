@@ -0,0 +1,24 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite_Diagnose(t *testing.T) {
+	s, err := FromDirectory("testdata/doctor", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	problems, err := s.Diagnose()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"_config.yml: exlude is not a configuration key; did you mean exclude?",
+		"plugin jekyll-archives is not emulated by gojekyll",
+		`_posts/2017-01-01-bad-date.md: can't parse date "last tuesday"`,
+		// but not vendor/bundle/…/_posts/welcome-to-jekyll.md, which is excluded
+		"_posts/undated.md: skipped, because its filename doesn't begin with a YYYY-MM-DD date",
+		"output paths differ only by case: Docs/index.html, docs/index.html",
+	}, problems)
+}
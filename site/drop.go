@@ -2,6 +2,7 @@ package site
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"github.com/osteele/gojekyll/pages"
@@ -29,6 +30,7 @@ func (s *Site) initializeDrop() error {
 		"collections":  s.collectionDrops(),
 		"data":         s.data,
 		"documents":    docs,
+		"env":          s.envVariables(),
 		"html_files":   s.htmlFiles(),
 		"html_pages":   s.htmlPages(),
 		"pages":        s.nonCollectionPages,
@@ -39,6 +41,10 @@ func (s *Site) initializeDrop() error {
 	for _, c := range s.Collections {
 		drop[c.Name] = c.Pages()
 	}
+	if s.cfg.IsMultilingual() {
+		drop["lang"] = s.cfg.DefaultLanguage()
+		drop["default_lang"] = s.cfg.DefaultLanguage()
+	}
 	s.drop = drop
 	s.setPostVariables()
 	return s.runHooks(func(h plugins.Plugin) error {
@@ -46,6 +52,25 @@ func (s *Site) initializeDrop() error {
 	})
 }
 
+// PageSiteDrop returns the value of the site variable in a page's template
+// context. In a collection document, site.related_posts is the document's
+// related posts rather than the ten most recent posts. In a multilingual
+// site, site.lang is the page's language.
+func (s *Site) PageSiteDrop(p pages.Page) interface{} {
+	overrides := map[string]interface{}{}
+	if related, ok := p.FrontMatter()["related_posts"]; ok {
+		overrides["related_posts"] = related
+	}
+	if s.cfg.IsMultilingual() {
+		overrides["lang"] = p.Lang()
+	}
+	if len(overrides) == 0 {
+		return s
+	}
+	s.ToLiquid() // initializes s.drop
+	return liquid.IterationKeyedMap(templates.MergeVariableMaps(s.drop, overrides))
+}
+
 // The following functions are only used in the drop.
 //
 // Since the drop is cached, there's no effort to cache these too.
@@ -58,6 +83,18 @@ func (s *Site) collectionDrops() []interface{} {
 	return drops
 }
 
+// envVariables returns the environment variables that the configuration
+// allows templates to see. Unset variables are omitted.
+func (s *Site) envVariables() map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, name := range s.cfg.EnvVariables {
+		if value, ok := os.LookupEnv(name); ok {
+			m[name] = value
+		}
+	}
+	return m
+}
+
 func (s *Site) htmlFiles() (result []*pages.StaticFile) {
 	for _, p := range s.staticFiles() {
 		if p.OutputExt() == ".html" {
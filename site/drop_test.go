@@ -63,10 +63,38 @@ func TestSite_ToLiquid_static_files(t *testing.T) {
 	drop := readTestSiteDrop(t)
 	files, ok := drop["static_files"].([]*pages.StaticFile)
 	require.True(t, ok, fmt.Sprintf("static_files has type %T", drop["static_files"]))
-	require.Len(t, files, 1)
+	require.Len(t, files, 2)
 
 	f := files[0].ToLiquid().(tags.IterationKeyedMap)
 	require.IsType(t, "", f["path"])
 	require.IsType(t, time.Now(), f["modified_time"])
 	require.Equal(t, ".html", f["extname"])
+	require.Equal(t, false, f["sitemap"])
+	require.Nil(t, f["author"])
+	require.Nil(t, f["collection"])
+
+	f = files[1].ToLiquid().(tags.IterationKeyedMap)
+	require.Equal(t, "/coll1/attachment.txt", f["path"])
+	require.Equal(t, "coll1", f["collection"])
+	require.Nil(t, f["permalink"])
+}
+
+func TestSite_ToLiquid_taxonomies(t *testing.T) {
+	drop := readTestSiteDrop(t)
+	tagged, ok := drop["tags"].(map[string][]pages.Page)
+	require.True(t, ok, fmt.Sprintf("tags has type %T", drop["tags"]))
+	require.Len(t, tagged, 1)
+	require.Len(t, tagged["Go"], 1)
+	require.Len(t, drop["categories"], 0)
+
+	authors, ok := drop["authors"].(map[string][]pages.Page)
+	require.True(t, ok, fmt.Sprintf("authors has type %T", drop["authors"]))
+	require.Len(t, authors["Jane Doe"], 1)
+
+	index := drop["taxonomies"].(map[string]interface{})
+	terms := index["authors"].([]*taxonomyTerm)
+	require.Len(t, terms, 1)
+	term := terms[0].ToLiquid().(map[string]interface{})
+	require.Equal(t, "jane-doe", term["slug"])
+	require.Equal(t, 1, term["count"])
 }
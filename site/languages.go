@@ -0,0 +1,172 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/frontmatter"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/osteele/gojekyll/plugins"
+	"github.com/osteele/gojekyll/renderers"
+	"github.com/osteele/gojekyll/utils"
+)
+
+// readTranslations reads the translated strings for each language, from
+// _i18n/LANG.yml (or .yaml, or .json).
+func (s *Site) readTranslations() error {
+	s.translations = map[string]interface{}{}
+	for _, lang := range s.cfg.Languages {
+		for _, ext := range []string{".yml", ".yaml", ".json"} {
+			filename := filepath.Join(s.SourceDir(), config.I18nDir, lang+ext)
+			if _, err := os.Stat(filename); os.IsNotExist(err) {
+				continue
+			}
+			data, err := readDataFile(filename)
+			if err != nil {
+				return utils.WrapPathError(err, filename)
+			}
+			s.translations[lang] = data
+			break
+		}
+	}
+	return nil
+}
+
+// translationTables returns the tables that the t filter uses for a
+// language: the language's strings, then the default language's.
+func (s *Site) translationTables(lang string) (tables []interface{}) {
+	for _, l := range []string{lang, s.cfg.DefaultLanguage()} {
+		if t, ok := s.translations[l]; ok {
+			tables = append(tables, t)
+		}
+	}
+	return
+}
+
+// initializeLanguageRenderers creates a rendering manager for each language
+// besides the default, whose t filter uses that language's strings.
+func (s *Site) initializeLanguageRenderers() error {
+	s.langRenderers = map[string]*renderers.Manager{}
+	for _, lang := range s.cfg.Languages {
+		if lang == s.cfg.DefaultLanguage() {
+			continue
+		}
+		m := s.renderer.WithTranslations(s.translationTables(lang)...)
+		engine := m.TemplateEngine()
+		err := s.runHooks(func(p plugins.Plugin) error {
+			return p.ConfigureTemplateEngine(engine)
+		})
+		if err != nil {
+			return err
+		}
+		s.langRenderers[lang] = m
+	}
+	return nil
+}
+
+// LanguageRendererManager returns the rendering manager for a language.
+func (s *Site) LanguageRendererManager(lang string) renderers.Renderers {
+	if m, ok := s.langRenderers[lang]; ok {
+		return m
+	}
+	return s.RendererManager()
+}
+
+// addLanguageFallbacks fills out the output tree of each language. For each
+// page (outside a collection) in the default language that doesn't have a
+// translation into another language, it adds a copy of the page in that
+// language; for example, /fr/about.html renders about.md with the French
+// strings.
+//
+// Collection documents don't have fallbacks, so that each language's
+// collections list only the documents written in that language.
+func (s *Site) addLanguageFallbacks() error {
+	var (
+		defaultLang = s.cfg.DefaultLanguage()
+		translated  = map[string]bool{} // lang + " " + translation key
+		fallbacks   []pages.Page
+	)
+	for _, p := range s.nonCollectionPages {
+		translated[p.Lang()+" "+s.translationKey(p)] = true
+	}
+	for _, p := range s.nonCollectionPages {
+		if p.Lang() != defaultLang {
+			continue
+		}
+		key := s.translationKey(p)
+		for _, lang := range s.cfg.Languages {
+			if translated[lang+" "+key] {
+				continue
+			}
+			d, err := s.languageCopy(p, lang)
+			if err != nil {
+				return err
+			}
+			// A page that sets its language in its front matter doesn't have fallbacks.
+			if fp, ok := d.(pages.Page); ok && fp.Lang() == lang {
+				fallbacks = append(fallbacks, fp)
+			}
+		}
+	}
+	for _, p := range fallbacks {
+		s.AddDocument(p, true)
+		s.nonCollectionPages = append(s.nonCollectionPages, p)
+	}
+	return nil
+}
+
+// languageCopy reads or generates a page again, in another language.
+func (s *Site) languageCopy(p pages.Page, lang string) (pages.Document, error) {
+	if src, ok := s.dataPages[p]; ok {
+		d, err := s.newDataPage(src.generator, src.record, lang)
+		return d, utils.WrapError(err, "data_pages")
+	}
+	rel := filepath.ToSlash(s.RelativePath(p.Source()))
+	defaults := frontmatter.FrontMatter(s.cfg.GetFrontMatterDefaults(config.PagesScopeType, rel))
+	fm := defaults.Merged(frontmatter.FrontMatter{"lang": lang})
+	d, err := pages.NewFile(s, p.Source(), rel, fm)
+	return d, utils.WrapPathError(err, p.Source())
+}
+
+// translationKey returns the URL that a page shares with its translations:
+// its URL without the language prefix.
+func (s *Site) translationKey(p pages.Page) string {
+	url, lang := p.URL(), p.Lang()
+	if lang != s.cfg.DefaultLanguage() && strings.HasPrefix(url, "/"+lang+"/") {
+		return url[len(lang)+1:]
+	}
+	return url
+}
+
+// setTranslationVariables sets page.translations, for each page that has
+// translations, to its translations in the order of the site languages.
+func (s *Site) setTranslationVariables() {
+	order := map[string]int{}
+	for i, lang := range s.cfg.Languages {
+		order[lang] = i
+	}
+	groups := map[string][]pages.Page{}
+	for _, p := range s.Pages() {
+		key := s.translationKey(p)
+		groups[key] = append(groups[key], p)
+	}
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return order[group[i].Lang()] < order[group[j].Lang()]
+		})
+		for _, p := range group {
+			var translations []pages.Page
+			for _, t := range group {
+				if t.Lang() != p.Lang() {
+					translations = append(translations, t)
+				}
+			}
+			if len(translations) > 0 {
+				p.FrontMatter()["translations"] = translations
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package site
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/stretchr/testify/require"
+)
+
+func readMultilingualSite(t *testing.T) *Site {
+	s, err := FromDirectory("testdata/i18n", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	return s
+}
+
+func TestSite_languages_routes(t *testing.T) {
+	s := readMultilingualSite(t)
+	for _, url := range []string{"/index.html", "/fr/index.html", "/about.html", "/fr/about.html"} {
+		require.Contains(t, s.Routes, url)
+	}
+	require.Empty(t, s.RouteCollisions())
+
+	about := s.Routes["/about.html"].(pages.Page)
+	require.Equal(t, "en", about.Lang())
+	translations := about.FrontMatter()["translations"].([]pages.Page)
+	require.Len(t, translations, 1)
+	require.Equal(t, "/fr/about.html", translations[0].URL())
+	require.Equal(t, "fr", translations[0].Lang())
+
+	posts := s.Posts()
+	require.Len(t, posts, 2)
+	for _, p := range posts {
+		require.Len(t, p.FrontMatter()["translations"], 1)
+	}
+}
+
+func TestSite_languages_render(t *testing.T) {
+	s := readMultilingualSite(t)
+	render := func(url string) string {
+		buf := new(bytes.Buffer)
+		require.NoError(t, s.WriteDocument(buf, s.Routes[url]))
+		return buf.String()
+	}
+	require.Equal(t, "Home About en en\n", render("/index.html"))
+	require.Equal(t, "Accueil About fr fr\n", render("/fr/index.html"))
+}
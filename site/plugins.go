@@ -1,6 +1,7 @@
 package site
 
 import (
+	"github.com/osteele/gojekyll/pages"
 	"github.com/osteele/gojekyll/plugins"
 	"github.com/osteele/gojekyll/utils"
 )
@@ -28,13 +29,31 @@ func (s *Site) installPlugins() error {
 }
 
 func (s *Site) runHooks(h func(plugins.Plugin) error) error {
+	return s.runNamedHooks(func(_ string, p plugins.Plugin) error { return h(p) })
+}
+
+// runNamedHooks is like runHooks, but it also passes the plugin name to h.
+func (s *Site) runNamedHooks(h func(string, plugins.Plugin) error) error {
 	for _, name := range s.plugins {
 		p, ok := plugins.Lookup(name)
 		if ok {
-			if err := h(p); err != nil {
+			if err := h(name, p); err != nil {
 				return utils.WrapError(err, "running plugin")
 			}
 		}
 	}
 	return nil
 }
+
+// pluginSite is the site that a plugin's PostReadSite hook receives. It
+// records the plugin as the source of the documents that the plugin adds, for
+// route collision messages.
+type pluginSite struct {
+	*Site
+	plugin string
+}
+
+// AddDocument is in the plugins.Site interface.
+func (s pluginSite) AddDocument(d pages.Document, output bool) {
+	s.addDocument(d, output, s.plugin)
+}
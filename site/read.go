@@ -25,16 +25,24 @@ func FromDirectory(dir string, flags config.Flags) (*Site, error) {
 
 // Read loads the site data and files.
 func (s *Site) Read() error {
+	if err := s.checkTaxonomyNames(); err != nil {
+		return err
+	}
 	if err := s.installPlugins(); err != nil {
 		return utils.WrapError(err, "initializing plugins")
 	}
 	s.Routes = make(map[string]pages.Document)
+	s.routeSources = map[string]string{}
+	s.routeCollisions = nil
 	if err := s.findTheme(); err != nil {
 		return utils.WrapError(err, "finding theme")
 	}
 	if err := s.readDataFiles(); err != nil {
 		return utils.WrapError(err, "reading data files")
 	}
+	if err := s.readTranslations(); err != nil {
+		return utils.WrapError(err, "reading translations")
+	}
 	if err := s.readThemeAssets(); err != nil {
 		return utils.WrapError(err, "reading theme assets")
 	}
@@ -44,6 +52,18 @@ func (s *Site) Read() error {
 	if err := s.ReadCollections(); err != nil {
 		return utils.WrapError(err, "reading collections")
 	}
+	if err := s.checkTaxonomySlugs(); err != nil {
+		return err
+	}
+	if err := s.generateDataPages(); err != nil {
+		return err
+	}
+	if s.cfg.IsMultilingual() {
+		if err := s.addLanguageFallbacks(); err != nil {
+			return utils.WrapError(err, "adding language fallbacks")
+		}
+		s.setTranslationVariables()
+	}
 	if err := s.initializeRenderers(); err != nil {
 		return utils.WrapError(err, "initializing renderers")
 	}
@@ -55,7 +75,13 @@ func (s *Site) Read() error {
 			return err
 		}
 	}
-	return s.runHooks(func(p plugins.Plugin) error { return p.PostReadSite(s) })
+	if err := s.runNamedHooks(func(name string, p plugins.Plugin) error { return p.PostReadSite(pluginSite{s, name}) }); err != nil {
+		return err
+	}
+	if s.cfg.Strict {
+		return s.routeCollisionsError()
+	}
+	return nil
 }
 
 // readFiles scans the source directory and creates pages and collection.
@@ -75,7 +101,15 @@ func (s *Site) readFiles(dir, base string) error {
 		case strings.HasPrefix(rel, "_"):
 			return nil
 		}
-		defaultFrontmatter := s.cfg.GetFrontMatterDefaults("", rel)
+		static, err := pages.IsStaticFile(s, filename, filepath.ToSlash(rel))
+		if err != nil {
+			return utils.WrapPathError(err, filename)
+		}
+		typename := config.PagesScopeType
+		if static {
+			typename = ""
+		}
+		defaultFrontmatter := s.cfg.GetFrontMatterDefaults(typename, rel)
 		d, err := pages.NewFile(s, filename, filepath.ToSlash(rel), defaultFrontmatter)
 		if err != nil {
 			return utils.WrapPathError(err, filename)
@@ -90,11 +124,21 @@ func (s *Site) readFiles(dir, base string) error {
 
 // AddDocument adds a document to the site's fields.
 // It ignores unpublished documents unless config.Unpublished is true.
+//
+// If another document already has the same URL, the new document replaces it,
+// and the collision is recorded in RouteCollisions. (A site file that replaces
+// a theme file isn't a collision.)
 func (s *Site) AddDocument(d pages.Document, output bool) {
+	s.addDocument(d, output, "")
+}
+
+// addDocument is AddDocument, for a document that plugin added, if plugin
+// isn't empty.
+func (s *Site) addDocument(d pages.Document, output bool, plugin string) {
 	if d.Published() || s.cfg.Unpublished {
 		s.docs = append(s.docs, d)
 		if output {
-			s.Routes[d.URL()] = d
+			s.addRoute(d, plugin)
 		}
 	}
 }
@@ -113,10 +157,14 @@ func (s *Site) ReadCollections() (err error) {
 		for _, p := range c.Pages() {
 			s.AddDocument(p, c.Output())
 		}
+		for _, f := range c.Files() {
+			s.AddDocument(f, c.Output())
+		}
 	}
 	sort.Slice(cols, func(i, j int) bool {
 		return cols[i].Name < cols[j].Name
 	})
 	s.Collections = cols
+	s.setRelatedPostsVariables()
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/osteele/gojekyll/pages"
 	"github.com/osteele/gojekyll/utils"
 )
 
@@ -12,18 +13,35 @@ import (
 // rebuilds the site. It sends status messages (strings) and errors to its output
 // channel.
 //
+// When a rebuild reads a new site, it watches that site's files instead.
+//
 // TODO use a logger instead of a message channel?
 func (s *Site) WatchRebuild() (<-chan interface{}, error) {
 	var (
 		messages      = make(chan interface{})
-		filesets, err = s.WatchFiles()
+		done          = make(chan struct{})
+		filesets, err = s.WatchFilesUntil(done)
 	)
 	if err != nil {
 		return nil, err
 	}
 	go func() {
-		for fileset := range filesets {
-			s = s.processFilesEvent(fileset, messages)
+		for {
+			// not a range loop, since filesets changes when the site does
+			fileset, ok := <-filesets
+			if !ok {
+				return
+			}
+			r := s.processFilesEvent(fileset, messages)
+			if r == s {
+				continue
+			}
+			close(done)
+			s, done = r, make(chan struct{})
+			if filesets, err = s.WatchFilesUntil(done); err != nil {
+				messages <- err
+				return
+			}
 		}
 	}()
 	return messages, nil
@@ -33,15 +51,22 @@ func (s *Site) WatchRebuild() (<-chan interface{}, error) {
 // build --incremental and site --incremental use this.
 func (s *Site) Reloaded(paths []string) (*Site, error) {
 	if s.RequiresFullReload(paths) {
-		copy, err := FromDirectory(s.SourceDir(), s.flags)
-		if err != nil {
-			return nil, err
-		}
-		s = copy
+		return s.Reread()
 	}
 	return s, s.Read()
 }
 
+// Reread returns a new site that reads the same source directory,
+// configuration file, and load flags. It doesn't modify s, so s can still be
+// used while the new site is read. serve uses this.
+func (s *Site) Reread() (*Site, error) {
+	copy, err := FromDirectory(s.SourceDir(), s.flags)
+	if err != nil {
+		return nil, err
+	}
+	return copy, copy.Read()
+}
+
 func (s *Site) processFilesEvent(fileset FilesEvent, messages chan<- interface{}) *Site {
 	// similar code to server.reload
 	messages <- fmt.Sprintf("Regenerating: %s...", fileset)
@@ -76,6 +101,9 @@ func (s *Site) rebuild(paths []string) (r *Site, n int, err error) {
 			if err != nil {
 				return
 			}
+			if p, ok := d.(pages.Page); ok && p.IsPost() {
+				s.invalidateRelatedPosts()
+			}
 			err = s.WriteDoc(d)
 			if err != nil {
 				return
@@ -0,0 +1,273 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/cache"
+	"github.com/osteele/gojekyll/frontmatter"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/osteele/gojekyll/utils"
+)
+
+// The number of related posts, as in Jekyll.
+const relatedPostsLimit = 10
+
+// Weights of tag and category overlap, relative to the text similarity.
+const (
+	relatedTagsWeight       = 0.5
+	relatedCategoriesWeight = 0.25
+)
+
+// relatedPostsDrop is the value of page.related_posts. It is computed when a
+// template first asks for it, since the related posts index isn't
+// available until all the posts have been read.
+type relatedPostsDrop struct {
+	site *Site
+	page pages.Page
+}
+
+// ToLiquid is in the liquid.Drop interface.
+func (d relatedPostsDrop) ToLiquid() interface{} {
+	return d.site.RelatedPosts(d.page)
+}
+
+// setRelatedPostsVariables sets page.related_posts for the collection
+// documents. The collections have just been (re-)read, so it also discards
+// the related posts index, which refers to the previous pages.
+func (s *Site) setRelatedPostsVariables() {
+	s.invalidateRelatedPosts()
+	for _, c := range s.Collections {
+		for _, p := range c.Pages() {
+			p.FrontMatter()["related_posts"] = relatedPostsDrop{s, p}
+		}
+	}
+}
+
+// RelatedPosts returns the posts that are most similar to p, most similar
+// first. Similarity combines the TF-IDF cosine similarity of the posts' text
+// with the overlap of their tags and categories.
+//
+// The text is the post source with its front matter, Liquid tags and HTML
+// tags removed, not the rendered text. Rendering a post can require its
+// related posts, and the source text lets the index be cached by content.
+func (s *Site) RelatedPosts(p pages.Page) []pages.Page {
+	s.relatedMutex.Lock()
+	defer s.relatedMutex.Unlock()
+	if s.relatedPosts == nil {
+		related, err := s.computeRelatedPosts()
+		if err != nil {
+			// related_posts is a convenience; don't fail the build for it
+			related = map[string][]pages.Page{}
+		}
+		s.relatedPosts = related
+	}
+	return s.relatedPosts[p.Source()]
+}
+
+// invalidateRelatedPosts causes the next call to RelatedPosts to re-compute the index.
+func (s *Site) invalidateRelatedPosts() {
+	s.relatedMutex.Lock()
+	defer s.relatedMutex.Unlock()
+	s.relatedPosts = nil
+}
+
+// computeRelatedPosts returns a map from each post's source path to its related posts.
+//
+// The index depends only on the post sources, so it is stored in the file
+// cache and re-used by builds, including incremental builds, that don't
+// change a post.
+func (s *Site) computeRelatedPosts() (map[string][]pages.Page, error) {
+	var (
+		posts  = s.Posts()
+		docs   = make([]relatedDoc, len(posts))
+		bySrc  = map[string]pages.Page{}
+		header = new(bytes.Buffer)
+	)
+	for i, p := range posts {
+		text, err := readBodyText(p.Source())
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = relatedDoc{p.Source(), text, p.Tags(), p.Categories()}
+		bySrc[p.Source()] = p
+		if err := json.NewEncoder(header).Encode(docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	index, err := cache.WithFile("related_posts", header.String(), func() (string, error) {
+		b, err := json.Marshal(rankRelatedDocs(docs, relatedPostsLimit))
+		return string(b), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var keys map[string][]string
+	if err := json.Unmarshal([]byte(index), &keys); err != nil {
+		return nil, err
+	}
+	related := map[string][]pages.Page{}
+	for src, rs := range keys {
+		for _, r := range rs {
+			if p, ok := bySrc[r]; ok {
+				related[src] = append(related[src], p)
+			}
+		}
+	}
+	return related, nil
+}
+
+// readBodyText returns the content of the file after its front matter.
+//
+// This uses the source rather than the rendered text, so that the index
+// doesn't depend on which pages have been rendered when it's computed.
+func readBodyText(filename string) (string, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", utils.WrapPathError(err, filename)
+	}
+	if _, err := frontmatter.Read(&b, nil); err != nil {
+		return "", utils.WrapPathError(err, filename)
+	}
+	return string(b), nil
+}
+
+// A relatedDoc is the information about a post that the related posts index uses.
+type relatedDoc struct {
+	Key        string
+	Text       string
+	Tags       []string
+	Categories []string
+}
+
+var (
+	markupMatcher = regexp.MustCompile(`(?s){%.*?%}|{{.*?}}|<[^>]*>`)
+	wordMatcher   = regexp.MustCompile(`[[:alpha:]][[:alnum:]]+`)
+)
+
+// Words that carry too little information to relate posts. (IDF takes care
+// of most of these for large sites, but not small ones.)
+var stopWords = utils.MakeStringSet(strings.Fields(`
+	an as at be by if in is it of on or so to up we
+	about after all also and any are because been but can could did does for
+	from had has have her his how into its just more most not now one only our
+	out over she should some such than that the their them then there these
+	they this those through was were what when where which while who will
+	with would you your`))
+
+// termFrequencies returns the relative frequency of each term in the text.
+func termFrequencies(text string) map[string]float64 {
+	text = markupMatcher.ReplaceAllString(text, " ")
+	tf := map[string]float64{}
+	count := 0
+	for _, w := range wordMatcher.FindAllString(strings.ToLower(text), -1) {
+		if !stopWords[w] {
+			tf[w]++
+			count++
+		}
+	}
+	for w := range tf {
+		tf[w] /= float64(count)
+	}
+	return tf
+}
+
+// rankRelatedDocs returns a map from each document key to the keys of the
+// (up to) limit most related other documents, most related first. Documents
+// with nothing in common aren't related.
+func rankRelatedDocs(docs []relatedDoc, limit int) map[string][]string {
+	var (
+		n       = len(docs)
+		vectors = make([]map[string]float64, n)
+		df      = map[string]int{}
+	)
+	for i, d := range docs {
+		vectors[i] = termFrequencies(d.Text)
+		for w := range vectors[i] {
+			df[w]++
+		}
+	}
+	norms := make([]float64, n)
+	for i, v := range vectors {
+		for w, tf := range v {
+			v[w] = tf * math.Log(float64(n)/float64(df[w]))
+			norms[i] += v[w] * v[w]
+		}
+		norms[i] = math.Sqrt(norms[i])
+	}
+	cosine := func(i, j int) float64 {
+		if norms[i] == 0 || norms[j] == 0 {
+			return 0
+		}
+		a, b := vectors[i], vectors[j]
+		if len(b) < len(a) {
+			a, b = b, a
+		}
+		dot := 0.0
+		for w, x := range a {
+			dot += x * b[w]
+		}
+		return dot / (norms[i] * norms[j])
+	}
+	type scored struct {
+		index int
+		score float64
+	}
+	result := map[string][]string{}
+	for i := range docs {
+		var candidates []scored
+		for j := range docs {
+			if i == j {
+				continue
+			}
+			score := cosine(i, j) +
+				relatedTagsWeight*jaccard(docs[i].Tags, docs[j].Tags) +
+				relatedCategoriesWeight*jaccard(docs[i].Categories, docs[j].Categories)
+			if score > 0 {
+				candidates = append(candidates, scored{j, score})
+			}
+		}
+		// stable, so that ties keep the collection's (date) order
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].score > candidates[b].score
+		})
+		if len(candidates) > limit {
+			candidates = candidates[:limit]
+		}
+		for _, c := range candidates {
+			result[docs[i].Key] = append(result[docs[i].Key], docs[c.index].Key)
+		}
+	}
+	return result
+}
+
+// jaccard returns the size of the intersection of the sets, divided by the
+// size of their union. Comparison is case-insensitive.
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := map[string]bool{}
+	for _, s := range a {
+		set[strings.ToLower(s)] = true
+	}
+	union, intersection := len(set), 0
+	seen := map[string]bool{}
+	for _, s := range b {
+		s = strings.ToLower(s)
+		switch {
+		case seen[s]:
+		case set[s]:
+			intersection++
+		default:
+			union++
+		}
+		seen[s] = true
+	}
+	return float64(intersection) / float64(union)
+}
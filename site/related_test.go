@@ -0,0 +1,71 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankRelatedDocs(t *testing.T) {
+	docs := []relatedDoc{
+		{Key: "go", Text: "Goroutines and channels make concurrency in Go pleasant."},
+		{Key: "ruby", Text: "Ruby blocks and gems.", Tags: []string{"ruby"}},
+		{Key: "go2", Text: "Channels and goroutines: more Go concurrency patterns."},
+		{Key: "rails", Text: "Deploying a web application.", Tags: []string{"Ruby"}},
+		{Key: "misc", Text: "Nothing in common here."},
+	}
+	related := rankRelatedDocs(docs, 10)
+	require.Equal(t, []string{"go2"}, related["go"])
+	require.Equal(t, []string{"go"}, related["go2"])
+	require.Equal(t, []string{"rails"}, related["ruby"])
+	require.Empty(t, related["misc"])
+
+	related = rankRelatedDocs(append(docs, relatedDoc{Key: "go3", Text: "Go concurrency"}), 1)
+	require.Len(t, related["go"], 1)
+}
+
+func TestJaccard(t *testing.T) {
+	require.Equal(t, 0.0, jaccard(nil, []string{"a"}))
+	require.Equal(t, 1.0, jaccard([]string{"a", "B"}, []string{"b", "A"}))
+	require.Equal(t, 0.5, jaccard([]string{"a", "b"}, []string{"b"}))
+}
+
+func TestTermFrequencies(t *testing.T) {
+	tf := termFrequencies(`The <em>cat</em> {% include x.html %} sat on {{ page.title }} the cat`)
+	require.Equal(t, map[string]float64{"cat": 2.0 / 3, "sat": 1.0 / 3}, tf)
+}
+
+func TestSite_RelatedPosts_incrementalReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gojekyll-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	write := func(rel, content string) {
+		filename := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+	}
+	write("_config.yml", "incremental: true\n")
+	write("_posts/2017-07-01-go.md", "---\ntags: [go]\n---\nGoroutines and channels.")
+	write("_posts/2017-07-02-go2.md", "---\ntags: [go]\n---\nChannels and goroutines.")
+
+	s, err := FromDirectory(dir, config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	related := s.RelatedPosts(s.Posts()[0])
+	require.Len(t, related, 1)
+
+	// the server reloads the same site when a post changes
+	write("_posts/2017-07-01-go.md", "---\ntags: [go, concurrency]\n---\nGoroutines and channels.")
+	r, err := s.Reloaded([]string{"_posts/2017-07-01-go.md"})
+	require.NoError(t, err)
+	require.True(t, r == s)
+	posts := s.Posts()
+	related = s.RelatedPosts(posts[0])
+	require.Len(t, related, 1)
+	require.True(t, related[0] == posts[1], "related posts are from the current read")
+	require.Equal(t, []string{"concurrency", "go"}, related[0].Tags())
+}
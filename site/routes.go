@@ -0,0 +1,64 @@
+package site
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/osteele/gojekyll/pages"
+)
+
+// A RouteCollision records that more than one document has the same URL.
+// Only the last document is written.
+type RouteCollision struct {
+	URL      string
+	Replaced string // description of the replaced document
+	Winner   string // description of the document that is written
+}
+
+func (c RouteCollision) Error() string {
+	return fmt.Sprintf("conflict: %s is the URL of both %s and %s", c.URL, c.Replaced, c.Winner)
+}
+
+// RouteCollisions returns the URL collisions between the documents that were
+// added while the site was read.
+func (s *Site) RouteCollisions() []RouteCollision {
+	return s.routeCollisions
+}
+
+func (s *Site) addRoute(d pages.Document, plugin string) {
+	url := d.URL()
+	if prev, found := s.Routes[url]; found && prev != d && !s.isThemeFile(prev) {
+		s.routeCollisions = append(s.routeCollisions, RouteCollision{url, s.routeSources[url], s.describeSource(d, plugin)})
+	}
+	s.Routes[url] = d
+	s.routeSources[url] = s.describeSource(d, plugin)
+}
+
+// describeSource returns the document's source path, and the plugin that
+// added it if any.
+func (s *Site) describeSource(d pages.Document, plugin string) string {
+	desc := "a generated page"
+	if src := d.Source(); src != "" {
+		desc = filepath.ToSlash(s.RelativePath(src))
+	}
+	if plugin != "" {
+		desc = fmt.Sprintf("%s (from plugin %s)", desc, plugin)
+	}
+	return desc
+}
+
+func (s *Site) isThemeFile(d pages.Document) bool {
+	return s.themeDir != "" && d.Source() != "" &&
+		strings.HasPrefix(d.Source(), s.themeDir+string(filepath.Separator))
+}
+
+// routeCollisionsError returns an error that lists the route collisions,
+// or nil if there are none.
+func (s *Site) routeCollisionsError() error {
+	errs := make([]error, len(s.routeCollisions))
+	for i, c := range s.routeCollisions {
+		errs[i] = c
+	}
+	return combineErrors(errs)
+}
@@ -25,14 +25,23 @@ type Site struct {
 	plugins  []string               // initially cfg.Plugins, but plugins can modify this this
 	themeDir string                 // absolute path to theme directory
 
+	routeSources    map[string]string // URL path -> description of the document's source
+	routeCollisions []RouteCollision
+
 	docs               []pages.Document // all documents, whether or not they are output
 	nonCollectionPages []pages.Page
+	dataPages          map[pages.Page]dataPageSource // pages that data_pages generates
 
-	renderer   *renderers.Manager
-	renderOnce sync.Once
+	renderer      *renderers.Manager
+	renderOnce    sync.Once
+	langRenderers map[string]*renderers.Manager // language -> manager; except the default language
+	translations  map[string]interface{}        // language -> strings, from _i18n
 
 	drop     map[string]interface{} // cached drop value
 	dropOnce sync.Once
+
+	relatedPosts map[string][]pages.Page // source path -> related posts; nil until computed
+	relatedMutex sync.Mutex
 }
 
 // SourceDir returns the site source directory.
@@ -169,15 +178,20 @@ func (s *Site) initializeRenderers() (err error) {
 	options := renderers.Options{
 		RelativeFilenameToURL: s.FilenameURLPath,
 		ThemeDir:              s.themeDir,
+		Translations:          s.translationTables(s.cfg.DefaultLanguage()),
 	}
 	s.renderer, err = renderers.New(s.cfg, options)
 	if err != nil {
 		return err
 	}
 	engine := s.renderer.TemplateEngine()
-	return s.runHooks(func(p plugins.Plugin) error {
+	err = s.runHooks(func(p plugins.Plugin) error {
 		return p.ConfigureTemplateEngine(engine)
 	})
+	if err != nil {
+		return err
+	}
+	return s.initializeLanguageRenderers()
 }
 
 // HasLayout is in the plugins.Site interface.
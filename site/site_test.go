@@ -1,6 +1,7 @@
 package site
 
 import (
+	"os"
 	"testing"
 
 	"github.com/osteele/gojekyll/config"
@@ -44,3 +45,45 @@ func TestExclude(t *testing.T) {
 	require.True(t, s.Exclude("~file"))
 	require.True(t, s.Exclude("file~"))
 }
+
+func TestSite_Read_nestedPosts(t *testing.T) {
+	s, err := FromDirectory("testdata/site2", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	posts := s.Posts()
+	require.Len(t, posts, 3)
+	require.Equal(t, []string{"news", "engineering", "infra"}, posts[0].Categories())
+	require.Equal(t, "/news/engineering/infra/2017/07/06/nested.html", posts[0].URL())
+	require.Empty(t, posts[1].Categories())
+	// directory categories are in path order, not sorted
+	require.Equal(t, []string{"zoology", "animals"}, posts[2].Categories())
+	require.Equal(t, "/zoology/animals/2017/07/04/deep.html", posts[2].URL())
+	require.Len(t, s.Pages(), 3)
+}
+
+func TestSite_RouteCollisions(t *testing.T) {
+	s, err := FromDirectory("testdata/collisions", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	require.Len(t, s.RouteCollisions(), 1)
+	c := s.RouteCollisions()[0]
+	require.Equal(t, "/same/", c.URL)
+	require.Equal(t, "a.md", c.Replaced)
+	require.Equal(t, "b.md", c.Winner)
+	require.Contains(t, c.Error(), "a.md and b.md")
+
+	s, err = FromDirectory("testdata/collisions", config.Flags{Strict: true})
+	require.NoError(t, err)
+	require.Error(t, s.Read())
+}
+
+func TestSite_Read_withoutTheme(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("testdata/site2"))
+	defer os.Chdir(wd) // nolint: errcheck
+	s, err := FromDirectory(".", config.Flags{})
+	require.NoError(t, err)
+	require.NoError(t, s.Read())
+	require.Empty(t, s.RouteCollisions())
+}
@@ -0,0 +1,196 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/osteele/gojekyll/pages"
+	"github.com/osteele/gojekyll/utils"
+)
+
+// A taxonomyTerm is a value of a taxonomy variable, such as a tag, together
+// with the posts that have that value.
+type taxonomyTerm struct {
+	Name  string // the most common spelling; or, of those, the first in sort order
+	Slug  string
+	Posts []pages.Page
+}
+
+// ToLiquid is in the liquid.Drop interface.
+func (t *taxonomyTerm) ToLiquid() interface{} {
+	return map[string]interface{}{
+		"name":  t.Name,
+		"slug":  t.Slug,
+		"count": len(t.Posts),
+		"posts": t.Posts,
+	}
+}
+
+// reservedSiteVariables are the site variables that a configured taxonomy
+// can't replace. Collection names are also reserved.
+var reservedSiteVariables = []string{
+	"collections", "data", "default_lang", "documents", "env", "html_files",
+	"html_pages", "lang", "pages", "posts", "related_posts", "static_files",
+	"taxonomies", "time", "url",
+}
+
+// checkTaxonomyNames returns an error if a configured taxonomy has the name
+// of a site variable that the taxonomy index would replace.
+func (s *Site) checkTaxonomyNames() error {
+	for _, name := range s.cfg.Taxonomies {
+		_, isCollection := s.cfg.Collections[name]
+		if isCollection || utils.StringArrayContains(reservedSiteVariables, name) {
+			return fmt.Errorf("taxonomy %q: site.%s is a reserved site variable", name, name)
+		}
+	}
+	return nil
+}
+
+// taxonomyNames returns the names of the site taxonomies. These are
+// categories and tags, and the taxonomies in the configuration file.
+func (s *Site) taxonomyNames() []string {
+	names := []string{"categories", "tags"}
+	for _, name := range s.cfg.Taxonomies {
+		if !utils.StringArrayContains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// taxonomyGetter returns a function that returns a page's values for the
+// named taxonomy.
+func (s *Site) taxonomyGetter(name string) func(pages.Page) []string {
+	switch name {
+	case "categories":
+		return func(p pages.Page) []string { return p.Categories() }
+	case "tags":
+		return func(p pages.Page) []string { return p.Tags() }
+	default:
+		return func(p pages.Page) []string { return taxonomyValues(p.FrontMatter()[name]) }
+	}
+}
+
+// taxonomyValues returns the terms of a custom taxonomy front matter value.
+// Unlike categories and tags, a string is a single term, so that for example
+// "author: Jane Doe" names one author.
+func taxonomyValues(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if item != nil {
+				result = append(result, fmt.Sprint(item))
+			}
+		}
+		return result
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// groupPagesBy returns the terms that getter returns for the pages, sorted
+// by name. Terms that differ only in case are merged.
+//
+// A term's name and slug depend only on the spellings of the term, not on
+// the other terms or on the order in which posts are read. Different terms
+// can therefore have the same slug; checkTaxonomySlugs rejects these.
+func groupPagesBy(ps []pages.Page, getter func(pages.Page) []string) []*taxonomyTerm {
+	var (
+		terms     []*taxonomyTerm
+		byKey     = map[string]*taxonomyTerm{}
+		spellings = map[*taxonomyTerm]map[string]int{}
+	)
+	for _, p := range ps {
+		seen := map[string]bool{}
+		for _, name := range getter(p) {
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			t, found := byKey[key]
+			if !found {
+				t = &taxonomyTerm{}
+				byKey[key] = t
+				spellings[t] = map[string]int{}
+				terms = append(terms, t)
+			}
+			spellings[t][name]++
+			t.Posts = append(t.Posts, p)
+		}
+	}
+	for _, t := range terms {
+		for name, n := range spellings[t] {
+			m := spellings[t][t.Name]
+			if t.Name == "" || n > m || n == m && name < t.Name {
+				t.Name = name
+			}
+		}
+		t.Slug = termSlug(t.Name)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		a, b := strings.ToLower(terms[i].Name), strings.ToLower(terms[j].Name)
+		return a < b
+	})
+	return terms
+}
+
+// termSlugReplacer spells out the punctuation that distinguishes common
+// terms, such as C, C# and C++, before they're slugified.
+var termSlugReplacer = strings.NewReplacer("#", "-sharp-", "+", "-plus-", "&", "-and-", "@", "-at-")
+
+// termSlug returns the slug of a taxonomy term.
+func termSlug(name string) string {
+	slug := strings.Trim(utils.Slugify(termSlugReplacer.Replace(name)), "-")
+	if slug == "" {
+		return "term"
+	}
+	return slug
+}
+
+// taxonomySlugCollisions describes the terms of each taxonomy that have the
+// same slug, and would therefore have the same URL in a page that lists them.
+func (s *Site) taxonomySlugCollisions() []string {
+	var problems []string
+	for _, name := range s.taxonomyNames() {
+		bySlug := map[string]string{}
+		for _, t := range groupPagesBy(s.Posts(), s.taxonomyGetter(name)) {
+			if other, found := bySlug[t.Slug]; found {
+				problems = append(problems, fmt.Sprintf("%s %q and %q have the same slug %q", name, other, t.Name, t.Slug))
+				continue
+			}
+			bySlug[t.Slug] = t.Name
+		}
+	}
+	return problems
+}
+
+// checkTaxonomySlugs returns an error if two terms of a taxonomy have the
+// same slug. A page that the site generates for each term would be written
+// to the same URL for both.
+func (s *Site) checkTaxonomySlugs() error {
+	var errs []error
+	for _, problem := range s.taxonomySlugCollisions() {
+		errs = append(errs, errors.New(problem))
+	}
+	return combineErrors(errs)
+}
+
+// termPages returns a map from term name to posts. This is the format of
+// Jekyll's site.categories and site.tags.
+func termPages(terms []*taxonomyTerm) map[string][]pages.Page {
+	m := map[string][]pages.Page{}
+	for _, t := range terms {
+		m[t.Name] = t.Posts
+	}
+	return m
+}
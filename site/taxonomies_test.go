@@ -0,0 +1,71 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/osteele/gojekyll/pages"
+	"github.com/stretchr/testify/require"
+)
+
+type taxonomyTestPage struct {
+	pages.Page
+	terms []string
+}
+
+func TestGroupPagesBy(t *testing.T) {
+	ps := []pages.Page{
+		taxonomyTestPage{terms: []string{"Go", "C++"}},
+		taxonomyTestPage{terms: []string{"go", "GO", "C#"}},
+		taxonomyTestPage{terms: []string{"c", "go"}},
+		taxonomyTestPage{terms: []string{"a.b", "a b"}},
+	}
+	terms := groupPagesBy(ps, func(p pages.Page) []string {
+		return p.(taxonomyTestPage).terms
+	})
+	require.Len(t, terms, 6)
+	var names, slugs []string
+	for _, term := range terms {
+		names = append(names, term.Name)
+		slugs = append(slugs, term.Slug)
+	}
+	// "go" is the most common spelling
+	require.Equal(t, []string{"a b", "a.b", "c", "C#", "C++", "go"}, names)
+	require.Equal(t, []string{"a-b", "a-b", "c", "c-sharp", "c-plus-plus", "go"}, slugs)
+	require.Len(t, terms[5].Posts, 3)
+
+	// a term's slug doesn't depend on the other terms
+	terms = groupPagesBy(ps[1:2], func(p pages.Page) []string {
+		return p.(taxonomyTestPage).terms
+	})
+	require.Equal(t, "c-sharp", terms[0].Slug)
+}
+
+func TestTaxonomyValues(t *testing.T) {
+	require.Nil(t, taxonomyValues(nil))
+	require.Equal(t, []string{"Jane Doe"}, taxonomyValues("Jane Doe"))
+	require.Equal(t, []string{"a", "1"}, taxonomyValues([]interface{}{"a", 1}))
+}
+
+func TestSite_checkTaxonomySlugs(t *testing.T) {
+	s, err := FromDirectory("testdata/taxonomies", config.Flags{})
+	require.NoError(t, err)
+	err = s.Read()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `tags "a b" and "a.b" have the same slug "a-b"`)
+}
+
+func TestSite_checkTaxonomyNames(t *testing.T) {
+	s := New(config.Flags{})
+	s.cfg.Taxonomies = []string{"authors", "series"}
+	require.NoError(t, s.checkTaxonomyNames())
+
+	for _, name := range []string{"posts", "pages", "data", "related_posts", "taxonomies"} {
+		s.cfg.Taxonomies = []string{name}
+		require.Error(t, s.checkTaxonomyNames(), name)
+	}
+
+	s.cfg.Collections = map[string]map[string]interface{}{"recipes": {}}
+	s.cfg.Taxonomies = []string{"recipes"}
+	require.Error(t, s.checkTaxonomyNames())
+}
@@ -31,6 +31,10 @@ func (s *Site) findTheme() error {
 }
 
 func (s *Site) readThemeAssets() error {
+	if s.themeDir == "" {
+		// otherwise this would read the assets directory of the working directory
+		return nil
+	}
 	err := s.readFiles(filepath.Join(s.themeDir, "assets"), s.themeDir)
 	if os.IsNotExist(err) {
 		return nil
@@ -27,7 +27,15 @@ func (e FilesEvent) String() string {
 
 // WatchFiles returns a channel that receives FilesEvent on changes within the site directory.
 func (s *Site) WatchFiles() (<-chan FilesEvent, error) {
-	filenames, err := s.makeFileWatcher()
+	return s.WatchFilesUntil(nil)
+}
+
+// WatchFilesUntil is like WatchFiles, but it stops watching, and closes its
+// channel, when done is closed. A watcher reads the include and exclude
+// settings of the site that created it; watch a reloaded site to pick up
+// changes to these.
+func (s *Site) WatchFilesUntil(done <-chan struct{}) (<-chan FilesEvent, error) {
+	filenames, err := s.makeFileWatcher(done)
 	if err != nil {
 		return nil, err
 	}
@@ -36,50 +44,124 @@ func (s *Site) WatchFiles() (<-chan FilesEvent, error) {
 		filesets  = make(chan FilesEvent)
 	)
 	go func() {
-		for {
-			paths := s.affectsBuildFilter(<-debounced)
-			if len(paths) > 0 {
-				// Create a new timestamp. Except under pathological
-				// circumstances, it will be close enough.
-				filesets <- FilesEvent{time.Now(), paths}
+		defer close(filesets)
+		for paths := range debounced {
+			paths = s.affectsBuildFilter(paths)
+			if len(paths) == 0 {
+				continue
+			}
+			// Create a new timestamp. Except under pathological
+			// circumstances, it will be close enough.
+			select {
+			case filesets <- FilesEvent{time.Now(), paths}:
+			case <-done:
 			}
 		}
 	}()
 	return filesets, nil
 }
 
-func (s *Site) makeFileWatcher() (<-chan string, error) {
+func (s *Site) makeFileWatcher(done <-chan struct{}) (<-chan string, error) {
 	switch {
 	case s.cfg.ForcePolling:
-		return s.makePollingWatcher()
+		return s.makePollingWatcher(done)
 	default:
-		return s.makeEventWatcher()
+		return s.makeEventWatcher(done)
 	}
 }
 
-func (s *Site) makeEventWatcher() (<-chan string, error) {
+// makeEventWatcher watches the directories of the source tree, except for
+// those that don't affect the build, and of the theme. fsnotify watches
+// aren't recursive, so it adds a watch for each directory, including those
+// that are created later.
+func (s *Site) makeEventWatcher(done <-chan struct{}) (<-chan string, error) {
 	var (
-		sourceDir = s.SourceDir()
+		sourceDir = utils.MustAbs(s.SourceDir())
 		filenames = make(chan string, 100)
 		w, err    = fsnotify.NewWatcher()
 	)
 	if err != nil {
 		return nil, err
 	}
+	roots := []string{sourceDir}
+	if s.themeDir != "" {
+		roots = append(roots, s.themeDir)
+	}
+	for _, root := range roots {
+		if err := s.addWatchedTree(w, root, nil); err != nil {
+			w.Close() // nolint: errcheck, gas
+			return nil, err
+		}
+	}
+	send := func(filename string) {
+		filenames <- utils.MustRel(sourceDir, filename)
+	}
 	go func() {
+		defer close(filenames)
 		for {
 			select {
 			case event := <-w.Events:
-				filenames <- utils.MustRel(sourceDir, event.Name)
+				if event.Op&fsnotify.Create != 0 {
+					// A directory can be created with files already
+					// in it, for example by mv or git checkout.
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() && s.watchesDir(event.Name) {
+						if err := s.addWatchedTree(w, event.Name, send); err != nil {
+							fmt.Fprintln(os.Stderr, "error:", err)
+						}
+					}
+				}
+				send(event.Name)
 			case err := <-w.Errors:
 				fmt.Fprintln(os.Stderr, "error:", err)
+			case <-done:
+				w.Close() // nolint: errcheck, gas
+				return
 			}
 		}
 	}()
-	return filenames, w.Add(sourceDir)
+	return filenames, nil
 }
 
-func (s *Site) makePollingWatcher() (<-chan string, error) {
+// addWatchedTree adds root, and the directories below it that watchesDir
+// selects, to w. If fn is non-nil, it's called with each file in the tree.
+func (s *Site) addWatchedTree(w *fsnotify.Watcher, root string, fn func(string)) error {
+	return filepath.Walk(root, func(filename string, info os.FileInfo, err error) error {
+		switch {
+		case err != nil && os.IsNotExist(err):
+			// the file was removed during the walk
+			return nil
+		case err != nil:
+			return err
+		case !info.IsDir():
+			if fn != nil {
+				fn(filename)
+			}
+			return nil
+		case filename != root && !s.watchesDir(filename):
+			return filepath.SkipDir
+		}
+		return utils.WrapPathError(w.Add(filename), filename)
+	})
+}
+
+// watchesDir returns true if the file watcher should watch a directory.
+// This excludes the destination directory, hidden directories, and
+// directories whose files don't affect the build. Theme directories are
+// watched even if the site excludes them.
+func (s *Site) watchesDir(dir string) bool {
+	var (
+		sourceDir = utils.MustAbs(s.SourceDir())
+		rel       = utils.MustRel(sourceDir, dir)
+	)
+	switch {
+	case dir == utils.MustAbs(s.DestDir()):
+		return false
+	default:
+		return s.fileAffectsBuild(rel)
+	}
+}
+
+func (s *Site) makePollingWatcher(done <-chan struct{}) (<-chan string, error) {
 	var (
 		sourceDir = utils.MustAbs(s.SourceDir())
 		filenames = make(chan string, 100)
@@ -96,7 +178,13 @@ func (s *Site) makePollingWatcher() (<-chan string, error) {
 	if err := w.Ignore(s.DestDir()); err != nil {
 		return nil, err
 	}
+	if s.themeDir != "" {
+		if err := w.AddRecursive(s.themeDir); err != nil {
+			return nil, err
+		}
+	}
 	go func() {
+		defer close(filenames)
 		for {
 			select {
 			case event := <-w.Event:
@@ -105,6 +193,9 @@ func (s *Site) makePollingWatcher() (<-chan string, error) {
 				fmt.Fprintln(os.Stderr, "error:", err)
 			case <-w.Closed:
 				return
+			case <-done:
+				w.Close()
+				return
 			}
 		}
 	}()
@@ -128,7 +219,11 @@ func debounce(interval time.Duration, input <-chan string) <-chan []string {
 	go func() {
 		for {
 			select {
-			case value := <-input:
+			case value, ok := <-input:
+				if !ok {
+					close(output)
+					return
+				}
 				if value == "." {
 					continue
 				}
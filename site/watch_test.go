@@ -0,0 +1,66 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/osteele/gojekyll/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite_WatchFilesUntil(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gojekyll-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	write := func(rel string) {
+		filename := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		require.NoError(t, ioutil.WriteFile(filename, []byte("title: text\n"), 0644))
+	}
+	write("_config.yml")
+	write("_posts/2017-07-01-post.md")
+	write("assets/css/main.css")
+	write("node_modules/package/index.js")
+	write("_site/index.html")
+
+	s, err := FromDirectory(dir, config.Flags{})
+	require.NoError(t, err)
+	done := make(chan struct{})
+	events, err := s.WatchFilesUntil(done)
+	require.NoError(t, err)
+	next := func() []string {
+		select {
+		case e := <-events:
+			sort.Strings(e.Paths)
+			return e.Paths
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+
+	// nested directories
+	write("_posts/2017-07-01-post.md")
+	write("assets/css/main.css")
+	require.Equal(t, []string{"_posts/2017-07-01-post.md", "assets/css/main.css"}, next())
+
+	// excluded files and the destination don't send events
+	write("node_modules/package/index.js")
+	write("_site/index.html")
+	write("about.md")
+	require.Equal(t, []string{"about.md"}, next())
+
+	// a new directory, and a file in it
+	write("new/dir/page.md")
+	paths := next()
+	require.Contains(t, paths, "new/dir/page.md")
+	write("new/dir/page.md")
+	require.Equal(t, []string{"new/dir/page.md"}, next())
+
+	close(done)
+	for range events {
+	}
+}
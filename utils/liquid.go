@@ -3,22 +3,30 @@ package utils
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 
 	"github.com/osteele/liquid"
 )
 
 // FollowDots applied to a property list ["a", "b", "c"] is equivalent to
 // the Liquid data expression "data.a.b.c", except without special treatment
-// of "first", "last", and "size".
+// of "first", "last", and "size". A numeric property indexes a list.
 func FollowDots(data interface{}, props []string) (interface{}, error) {
 	for _, name := range props {
 		data = liquid.FromDrop(data)
-		if reflect.TypeOf(data).Kind() == reflect.Map {
+		switch reflect.ValueOf(data).Kind() {
+		case reflect.Map:
 			item := reflect.ValueOf(data).MapIndex(reflect.ValueOf(name))
 			if item.IsValid() && (item.Kind() != reflect.Ptr || !item.IsNil()) && item.CanInterface() {
 				data = item.Interface()
 				continue
 			}
+		case reflect.Slice:
+			items := reflect.ValueOf(data)
+			if i, err := strconv.Atoi(name); err == nil && 0 <= i && i < items.Len() {
+				data = items.Index(i).Interface()
+				continue
+			}
 		}
 		return nil, fmt.Errorf("no such property: %q", name)
 	}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowDots(t *testing.T) {
+	data := map[string]interface{}{
+		"a":    map[string]interface{}{"b": "c"},
+		"list": []interface{}{"x", map[string]interface{}{"y": "z"}},
+		"nil":  nil,
+	}
+	v, err := FollowDots(data, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, "c", v)
+
+	v, err = FollowDots(data, []string{"list", "1", "y"})
+	require.NoError(t, err)
+	require.Equal(t, "z", v)
+
+	_, err = FollowDots(data, []string{"list", "2"})
+	require.Error(t, err)
+	_, err = FollowDots(data, []string{"a", "x"})
+	require.Error(t, err)
+	_, err = FollowDots(data, []string{"nil", "x"})
+	require.Error(t, err)
+}
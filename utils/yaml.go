@@ -5,22 +5,17 @@ import yaml "gopkg.in/yaml.v2"
 // UnmarshalYAMLInterface is a wrapper for yaml.Unmarshal that
 // knows how to unmarshal maps and lists.
 func UnmarshalYAMLInterface(b []byte, i *interface{}) error {
-	var m yaml.MapSlice
-	err := yaml.Unmarshal(b, &m)
-	switch err.(type) {
-	case *yaml.TypeError:
-		// Work around https://github.com/go-yaml/yaml/issues/20
-		var s []interface{}
-		err = yaml.Unmarshal(b, &s)
-		if err != nil {
-			return err
-		}
+	// Work around https://github.com/go-yaml/yaml/issues/20. A list of maps
+	// unmarshals into a MapSlice without an error, so check for a list first.
+	var s []interface{}
+	if err := yaml.Unmarshal(b, &s); err == nil && s != nil {
 		*i = s
-	default:
-		if err != nil {
-			return err
-		}
-		*i = m
+		return nil
+	}
+	var m yaml.MapSlice
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return err
 	}
+	*i = m
 	return nil
 }
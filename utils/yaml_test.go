@@ -10,6 +10,7 @@ import (
 
 const mapYaml = "a: 1\nb: 2"
 const listYaml = "- a\n- b"
+const mapListYaml = "- a: 1\n- b: 2"
 
 func TestUnmarshalYAML(t *testing.T) {
 	var d interface{}
@@ -33,4 +34,10 @@ func TestUnmarshalYAML(t *testing.T) {
 	default:
 		require.IsType(t, d, map[interface{}]interface{}{})
 	}
+
+	err = UnmarshalYAMLInterface([]byte(mapListYaml), &d)
+	require.NoError(t, err)
+	require.IsType(t, []interface{}{}, d)
+	require.Len(t, d, 2)
+	require.Equal(t, map[interface{}]interface{}{"a": 1}, d.([]interface{})[0])
 }